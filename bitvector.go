@@ -0,0 +1,131 @@
+package trietree
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// wordsPerBlock is the number of words in a block of rank directory.
+const wordsPerBlock = 8
+
+// bitVector is a sequence of bits which supports rank and select operations.
+// Call build after pushing all bits, to use rank and select.
+type bitVector struct {
+	words []uint64
+	n     int
+	// ranks is the number of ones before each block, and the last one is the
+	// number of all ones.
+	ranks []int
+}
+
+// push appends a bit.
+func (bv *bitVector) push(b bool) {
+	if bv.n%64 == 0 {
+		bv.words = append(bv.words, 0)
+	}
+	if b {
+		bv.words[bv.n/64] |= 1 << (bv.n % 64)
+	}
+	bv.n++
+}
+
+// get returns the i'th bit.
+func (bv *bitVector) get(i int) bool {
+	return bv.words[i/64]&(1<<(i%64)) != 0
+}
+
+// build builds the rank directory.
+func (bv *bitVector) build() {
+	nblocks := (len(bv.words) + wordsPerBlock - 1) / wordsPerBlock
+	bv.ranks = make([]int, nblocks+1)
+	c := 0
+	for i, w := range bv.words {
+		if i%wordsPerBlock == 0 {
+			bv.ranks[i/wordsPerBlock] = c
+		}
+		c += bits.OnesCount64(w)
+	}
+	bv.ranks[nblocks] = c
+}
+
+// rank1 returns the number of ones in [0, i).
+func (bv *bitVector) rank1(i int) int {
+	x := i / 64
+	c := bv.ranks[x/wordsPerBlock]
+	for j := x / wordsPerBlock * wordsPerBlock; j < x; j++ {
+		c += bits.OnesCount64(bv.words[j])
+	}
+	if r := i % 64; r > 0 {
+		c += bits.OnesCount64(bv.words[x] & (1<<r - 1))
+	}
+	return c
+}
+
+// rank0 returns the number of zeros in [0, i).
+func (bv *bitVector) rank0(i int) int {
+	return i - bv.rank1(i)
+}
+
+// select1 returns the position of the k'th (0-origin) one.
+func (bv *bitVector) select1(k int) int {
+	return bv.selectBit(k, true)
+}
+
+// select0 returns the position of the k'th (0-origin) zero.
+func (bv *bitVector) select0(k int) int {
+	return bv.selectBit(k, false)
+}
+
+func (bv *bitVector) selectBit(k int, one bool) int {
+	count := func(b int) int {
+		if one {
+			return bv.ranks[b]
+		}
+		return b*wordsPerBlock*64 - bv.ranks[b]
+	}
+	// find the last block which has k or less bits before it.
+	b := sort.Search(len(bv.ranks), func(b int) bool {
+		return count(b) > k
+	}) - 1
+	k -= count(b)
+	for x := b * wordsPerBlock; x < len(bv.words); x++ {
+		w := bv.words[x]
+		if !one {
+			w = ^w
+		}
+		if c := bits.OnesCount64(w); k >= c {
+			k -= c
+			continue
+		}
+		for ; k > 0; k-- {
+			w &= w - 1
+		}
+		return x*64 + bits.TrailingZeros64(w)
+	}
+	return bv.n
+}
+
+func (bv *bitVector) write(w *writer) {
+	w.writeInt(bv.n)
+	for _, v := range bv.words {
+		w.writeInt64(int64(v))
+	}
+}
+
+func (bv *bitVector) read(r *reader) error {
+	n, err := r.readSize()
+	if err != nil {
+		return err
+	}
+	bv.n = n
+	bv.words = make([]uint64, (n+63)/64)
+	for i := range bv.words {
+		v, _ := r.readInt64()
+		bv.words[i] = uint64(v)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	bv.build()
+	return nil
+}
@@ -0,0 +1,39 @@
+package trietree
+
+// Count counts matches of each key in s, and adds them to counts which is
+// indexed by edge ID - 1.  Matches of IDs which exceed the length of counts
+// are ignored.  It counts all matches including overlapping ones, like Scan
+// and Predict.  It doesn't allocate any memory.
+func (st *STree) Count(s string, counts []uint32) {
+	curr := 0
+	for i := 0; i < len(s); {
+		c, sz := decodeLabel(s[i:], st.ByteLabels)
+		i += sz
+		curr = st.nextNode(curr, st.normalize(c))
+		for n := matchedNode[int](st, curr); n > 0; n = st.nodeOutput(n) {
+			if id := st.Nodes[n].EdgeID; id <= len(counts) {
+				counts[id-1]++
+			}
+		}
+	}
+}
+
+// Present sets bits for keys which appear in s.  The bit for a key is the
+// (ID-1)%64'th bit of bits[(ID-1)/64].  Bits of IDs which exceed the length
+// of bits are ignored.  Bits which were set before are kept.  It finds all
+// matches including overlapping ones, like Scan and Predict.  It doesn't
+// allocate any memory.
+func (st *STree) Present(s string, bits []uint64) {
+	curr := 0
+	for i := 0; i < len(s); {
+		c, sz := decodeLabel(s[i:], st.ByteLabels)
+		i += sz
+		curr = st.nextNode(curr, st.normalize(c))
+		for n := matchedNode[int](st, curr); n > 0; n = st.nodeOutput(n) {
+			id := st.Nodes[n].EdgeID
+			if x := (id - 1) / 64; x < len(bits) {
+				bits[x] |= 1 << ((id - 1) % 64)
+			}
+		}
+	}
+}
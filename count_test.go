@@ -0,0 +1,56 @@
+package trietree_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+func TestSTree_Count(t *testing.T) {
+	dt := testDTreePut(t, &trietree.DTree{}, "ab", "bc", "bab", "d", "abcde", "あい", "い")
+	st := trietree.Freeze(dt)
+	for _, s := range []string{"", "xyz", "abcde", "babab", "あいいd"} {
+		want := make([]uint32, 7)
+		for p := range st.Predict(s) {
+			want[p.ID-1]++
+		}
+		got := make([]uint32, 7)
+		st.Count(s, got)
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("unexpected counts for %q: -want +got\n%s", s, d)
+		}
+
+		var wantBits uint64
+		for i, n := range want {
+			if n > 0 {
+				wantBits |= 1 << i
+			}
+		}
+		bits := []uint64{0}
+		st.Present(s, bits)
+		if bits[0] != wantBits {
+			t.Errorf("unexpected bits for %q: want=%b got=%b", s, wantBits, bits[0])
+		}
+	}
+
+	// short buffers ignore IDs which exceed them.
+	counts := make([]uint32, 1)
+	st.Count("abab", counts)
+	if counts[0] != 2 {
+		t.Errorf("unexpected count: %d", counts[0])
+	}
+	st.Present("abab", nil)
+}
+
+func TestSTree_CountAllocs(t *testing.T) {
+	st := trietree.Freeze(testDTreePut(t, &trietree.DTree{}, "ab", "bc", "bab", "d", "abcde"))
+	counts := make([]uint32, 5)
+	bits := make([]uint64, 1)
+	if n := testing.AllocsPerRun(100, func() {
+		st.Count("abcdebabab", counts)
+		st.Present("abcdebabab", bits)
+	}); n != 0 {
+		t.Errorf("unexpected allocations: %f", n)
+	}
+}
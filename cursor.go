@@ -0,0 +1,88 @@
+package trietree
+
+import "iter"
+
+// Cursor navigates a tree step by step from the root.
+// Modifying a tree invalidates its cursors.
+type Cursor interface {
+	// Step moves the cursor to a child node with label r, which is
+	// normalized by Normalizer of the tree.  It returns false and the cursor
+	// stays when there are no such child.
+	Step(r rune) bool
+
+	// Edge returns an edge ID of the current node.  ok will be false when
+	// the current node has no corresponding key.
+	Edge() (id int, ok bool)
+
+	// Children returns an iterator which enumerates labels of child nodes of
+	// the current node, in order.
+	Children() iter.Seq[rune]
+
+	// Depth returns the number of steps from the root.
+	Depth() int
+
+	// Reset moves the cursor to the root.
+	Reset()
+
+	// Clone creates a copy of the cursor, which moves independently.
+	Clone() Cursor
+}
+
+// Cursor creates a new Cursor which points the root of the tree.
+func (dt *DTree) Cursor() Cursor {
+	return newCursor[*DNode](dt)
+}
+
+// Cursor creates a new Cursor which points the root of the tree.
+func (st *STree) Cursor() Cursor {
+	return newCursor[int](st)
+}
+
+type cursor[T comparable] struct {
+	tree  searchableTree[T]
+	node  T
+	depth int
+}
+
+func newCursor[T comparable](tree searchableTree[T]) *cursor[T] {
+	return &cursor[T]{tree: tree, node: tree.root()}
+}
+
+func (c *cursor[T]) Step(r rune) bool {
+	next, ok := c.tree.child(c.node, c.tree.normalize(r))
+	if !ok {
+		return false
+	}
+	c.node = next
+	c.depth++
+	return true
+}
+
+func (c *cursor[T]) Edge() (id int, ok bool) {
+	id = c.tree.nodeId(c.node)
+	return id, id > 0
+}
+
+func (c *cursor[T]) Children() iter.Seq[rune] {
+	return func(yield func(rune) bool) {
+		for r := range c.tree.children(c.node) {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
+func (c *cursor[T]) Depth() int {
+	return c.depth
+}
+
+func (c *cursor[T]) Reset() {
+	c.node = c.tree.root()
+	c.depth = 0
+}
+
+func (c *cursor[T]) Clone() Cursor {
+	c2 := *c
+	return &c2
+}
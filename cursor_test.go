@@ -0,0 +1,67 @@
+package trietree_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+func testCursor(t *testing.T, c trietree.Cursor) {
+	t.Helper()
+	check := func(wantDepth, wantID int, wantChildren []rune) {
+		t.Helper()
+		if d := c.Depth(); d != wantDepth {
+			t.Errorf("unexpected depth: want=%d got=%d", wantDepth, d)
+		}
+		id, ok := c.Edge()
+		if id != wantID || ok != (wantID > 0) {
+			t.Errorf("unexpected edge: want=%d got=(%d, %t)", wantID, id, ok)
+		}
+		if d := cmp.Diff(wantChildren, slices.Collect(c.Children())); d != "" {
+			t.Errorf("unexpected children: -want +got\n%s", d)
+		}
+	}
+
+	check(0, 0, []rune{'a', 'b', 'あ'})
+	if !c.Step('a') {
+		t.Fatal("failed to step 'a'")
+	}
+	check(1, 1, []rune{'b'})
+	if c.Step('c') {
+		t.Fatal("unexpected step 'c'")
+	}
+	check(1, 1, []rune{'b'})
+	if !c.Step('b') {
+		t.Fatal("failed to step 'b'")
+	}
+	check(2, 2, []rune{'c', 'd'})
+
+	// clone moves independently.
+	c2 := c.Clone()
+	if !c2.Step('d') {
+		t.Fatal("failed to step 'd' on clone")
+	}
+	check(2, 2, []rune{'c', 'd'})
+	if id, _ := c2.Edge(); id != 4 || c2.Depth() != 3 {
+		t.Errorf("unexpected clone: id=%d depth=%d", id, c2.Depth())
+	}
+
+	c.Reset()
+	check(0, 0, []rune{'a', 'b', 'あ'})
+	if !c.Step('あ') || !c.Step('い') {
+		t.Fatal("failed to step \"あい\"")
+	}
+	check(2, 6, nil)
+}
+
+func TestCursor(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "abd", "b", "あい"}
+	t.Run("dynamic", func(t *testing.T) {
+		testCursor(t, testDTreePut(t, &trietree.DTree{}, keys...).Cursor())
+	})
+	t.Run("static", func(t *testing.T) {
+		testCursor(t, trietree.Freeze(testDTreePut(t, &trietree.DTree{}, keys...)).Cursor())
+	})
+}
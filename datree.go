@@ -0,0 +1,422 @@
+package trietree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+)
+
+// DATree is static tree in double-array form.  A child of node s with label
+// code c is placed at index base[s]+c, and check of the index holds s.  So
+// finding a child takes O(1) time per label.  DATree is built from DTree by
+// FreezeDA.
+type DATree struct {
+	// Normalizer is applied to queries when not nil.  It is not serialized,
+	// so set same one with the original DTree after ReadDA.
+	Normalizer Normalizer
+
+	// ByteLabels makes each byte of queries a label, instead of each rune
+	// decoded from UTF-8.  It is copied from DTree by FreezeDA, and
+	// serialized.
+	ByteLabels bool
+
+	codes  *codeMap
+	labels []rune // labels in order of codes.
+
+	base   []int
+	check  []int // index of parent node, or -1 for unused slots.
+	fail   []int
+	output []int
+	edges  []int // edge ID of each node.
+	depths []int // depth of each node, which is not serialized.
+	levels []int
+}
+
+// FreezeDA converts dynamic tree to static tree in double-array form.
+func FreezeDA(src *DTree) *DATree {
+	da := &DATree{
+		Normalizer: src.Normalizer,
+		ByteLabels: src.ByteLabels,
+		levels:     make([]int, src.lastEdgeID),
+	}
+
+	// assign codes to labels in order.
+	var (
+		labels  []rune
+		collect func(*DNode)
+	)
+	collect = func(dn *DNode) {
+		dn.Child.eachSiblings(func(c *DNode) {
+			labels = append(labels, c.Label)
+			collect(c)
+		})
+	}
+	collect(&src.Root)
+	slices.Sort(labels)
+	da.setLabels(slices.Compact(labels))
+
+	da.extend(1)
+	da.edges[0] = src.Root.EdgeID
+
+	type item struct {
+		dn *DNode
+		x  int
+	}
+	var (
+		queue    = []item{{&src.Root, 0}}
+		children []*DNode
+		codes    []int
+		free     = 1
+	)
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if p.dn.EdgeID > 0 {
+			da.levels[p.dn.EdgeID-1] = p.dn.Level
+		}
+		children, codes = children[:0], codes[:0]
+		p.dn.Child.eachSiblings(func(dn *DNode) {
+			children = append(children, dn)
+			codes = append(codes, da.codes.code(dn.Label))
+		})
+		if len(children) == 0 {
+			continue
+		}
+		// find a base which all children can be placed at.
+		for free < len(da.check) && da.check[free] >= 0 {
+			free++
+		}
+		b := max(free-codes[0], 0)
+		for !da.placeable(b, codes) {
+			b++
+		}
+		da.base[p.x] = b
+		da.extend(b + codes[len(codes)-1] + 1)
+		for i, dn := range children {
+			x := b + codes[i]
+			da.check[x] = p.x
+			da.edges[x] = dn.EdgeID
+			da.depths[x] = dn.Level
+			queue = append(queue, item{dn, x})
+		}
+		// fill failure links of children.  Failure nodes of children have
+		// been placed already, as those are shallower.
+		for i, dn := range children {
+			x := b + codes[i]
+			f := 0
+			if p.x != 0 {
+				f = da.nextNode(da.fail[p.x], dn.Label)
+			}
+			da.fail[x] = f
+			da.output[x] = da.outputOf(f)
+		}
+	}
+	return da
+}
+
+func (da *DATree) setLabels(labels []rune) {
+	da.codes = newCodeMap()
+	da.labels = labels
+	for _, r := range labels {
+		da.codes.add(r)
+	}
+}
+
+// placeable checks all slots for codes from base b are unused.
+func (da *DATree) placeable(b int, codes []int) bool {
+	for _, c := range codes {
+		if x := b + c; x < len(da.check) && da.check[x] >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// extend extends arrays to have n slots at least.
+func (da *DATree) extend(n int) {
+	for len(da.check) < n {
+		da.base = append(da.base, 0)
+		da.check = append(da.check, -1)
+		da.fail = append(da.fail, 0)
+		da.output = append(da.output, 0)
+		da.edges = append(da.edges, 0)
+		da.depths = append(da.depths, 0)
+	}
+}
+
+// outputOf returns an Output for nodes which have failure node x.
+func (da *DATree) outputOf(x int) int {
+	if x > 0 && da.edges[x] > 0 {
+		return x
+	}
+	return da.output[x]
+}
+
+// fillDepths fills depths by following check to the root.
+func (da *DATree) fillDepths() error {
+	known := make([]bool, len(da.check))
+	known[0] = true
+	var path []int
+	for x := range da.check {
+		if da.check[x] < 0 {
+			continue
+		}
+		path = path[:0]
+		for y := x; !known[y]; y = da.check[y] {
+			if da.check[y] < 0 || da.check[y] >= len(da.check) || len(path) >= len(da.check) {
+				return errors.New("broken double-array")
+			}
+			path = append(path, y)
+		}
+		for _, y := range slices.Backward(path) {
+			da.depths[y] = da.depths[da.check[y]] + 1
+			known[y] = true
+		}
+	}
+	return nil
+}
+
+// methods DATree satisfies predictableTree[int]
+func (da *DATree) root() int            { return 0 }
+func (da *DATree) byteLabels() bool     { return da.ByteLabels }
+func (da *DATree) nodeId(x int) int     { return da.edges[x] }
+func (da *DATree) nodeOutput(x int) int { return da.output[x] }
+func (da *DATree) nodeDepth(x int) int  { return da.depths[x] }
+func (da *DATree) nodeFail(x int) int   { return da.fail[x] }
+
+func (da *DATree) nodeLevel(x int) int {
+	if id := da.edges[x]; id > 0 && id-1 < len(da.levels) {
+		return da.levels[id-1]
+	}
+	return -1
+}
+
+// normalize applies Normalizer to a rune.
+func (da *DATree) normalize(r rune) rune {
+	if da.Normalizer == nil {
+		return r
+	}
+	return da.Normalizer(r)
+}
+
+func (da *DATree) child(x int, r rune) (int, bool) {
+	c := da.codes.code(r)
+	if c == 0 {
+		return 0, false
+	}
+	y := da.base[x] + c
+	if y >= len(da.check) || da.check[y] != x {
+		return 0, false
+	}
+	return y, true
+}
+
+func (da *DATree) nextNode(x int, r rune) int {
+	for {
+		if y, ok := da.child(x, r); ok {
+			return y
+		}
+		if x == 0 {
+			return 0
+		}
+		x = da.fail[x]
+	}
+}
+
+// Scan scans a string to find matched words.
+func (da *DATree) Scan(s string, r ScanReporter) error {
+	return da.ScanContext(context.Background(), s, r)
+}
+
+// ScanContext scans a string to find matched words.
+// ScanReporter r will receive reports for each characters when scan.
+func (da *DATree) ScanContext(ctx context.Context, s string, r ScanReporter) error {
+	return da.ScanWith(ctx, s, MatchOptions{}, r)
+}
+
+// ScanWith is same as ScanContext, but reports only matches which are
+// specified by opts.
+func (da *DATree) ScanWith(ctx context.Context, s string, opts MatchOptions, r ScanReporter) error {
+	return scanContext[int](ctx, da, s, r, opts)
+}
+
+// ScanBytes scans a byte slice to find matched words.
+func (da *DATree) ScanBytes(b []byte, r ScanReporter) error {
+	return scanContext[int](context.Background(), da, b, r, MatchOptions{})
+}
+
+// Predict returns an iterator which enumerates Prediction: key suggestions
+// that match the query in the tree.
+func (da *DATree) Predict(query string) iter.Seq[Prediction] {
+	return da.PredictWith(query, MatchOptions{})
+}
+
+// PredictWith is same as Predict, but reports only matches which are
+// specified by opts.
+func (da *DATree) PredictWith(query string, opts MatchOptions) iter.Seq[Prediction] {
+	return predict[int](da, query, opts)
+}
+
+// PredictBytes returns an iterator which enumerates Prediction: key
+// suggestions that match the query in the tree.
+func (da *DATree) PredictBytes(query []byte) iter.Seq[Prediction] {
+	return predict[int](da, query, MatchOptions{})
+}
+
+// PredictIter returns an iterator function PredictionIter, which enumerates
+// Prediction: key suggestions that match the query in the tree.
+func (da *DATree) PredictIter(query string) PredictionIter {
+	return da.PredictIterWith(query, MatchOptions{})
+}
+
+// PredictIterWith is same as PredictIter, but reports only matches which are
+// specified by opts.
+func (da *DATree) PredictIterWith(query string, opts MatchOptions) PredictionIter {
+	return predictIter[int](da, query, opts)
+}
+
+// Get finds an edge for key k, and returns its ID.
+// ok will be false when k is not a key of the tree.
+func (da *DATree) Get(k string) (edgeID int, ok bool) {
+	x := 0
+	for i := 0; i < len(k); {
+		r, sz := decodeLabel(k[i:], da.ByteLabels)
+		x, ok = da.child(x, da.normalize(r))
+		if !ok {
+			return 0, false
+		}
+		i += sz
+	}
+	edgeID = da.edges[x]
+	return edgeID, edgeID > 0
+}
+
+// LongestPrefix finds a longest prefix node/edge matches given s string.
+func (da *DATree) LongestPrefix(s string) (prefix string, edgeID int) {
+	end := 0
+	curr := 0
+	for i := 0; i < len(s); {
+		r, sz := decodeLabel(s[i:], da.ByteLabels)
+		next, ok := da.child(curr, da.normalize(r))
+		if !ok {
+			break
+		}
+		i += sz
+		if id := da.edges[next]; id > 0 {
+			edgeID = id
+			end = i
+		}
+		curr = next
+	}
+	return s[:end], edgeID
+}
+
+// daFormatVersion is the version of serialized format of DATree.  It is
+// written as a negated number at the head.
+const daFormatVersion = 1
+
+// Write serializes a tree to io.Writer.
+func (da *DATree) Write(w io.Writer) error {
+	ww := newWriter(w)
+
+	// write header.
+	var flags int
+	if da.ByteLabels {
+		flags |= flagByteLabels
+	}
+	ww.writeInt(-daFormatVersion)
+	ww.writeInt(flags)
+
+	// write labels.
+	ww.writeInt(len(da.labels))
+	for _, r := range da.labels {
+		ww.writeRune(r)
+	}
+
+	// write slots.
+	ww.writeInt(len(da.check))
+	for x := range da.check {
+		ww.writeInt(da.base[x])
+		ww.writeInt(da.check[x])
+		ww.writeInt(da.fail[x])
+		ww.writeInt(da.output[x])
+		ww.writeInt(da.edges[x])
+	}
+
+	// write levels.
+	ww.writeInt(len(da.levels))
+	for _, lv := range da.levels {
+		ww.writeInt(lv)
+	}
+	if ww.err != nil {
+		return ww.err
+	}
+	return ww.w.Flush()
+}
+
+// ReadDA reads static tree in double-array form from io.Reader.
+func ReadDA(r io.Reader) (*DATree, error) {
+	rr := newReader(r)
+
+	// read header.
+	if v := rr.readInt(); rr.err == nil && v != -daFormatVersion {
+		return nil, fmt.Errorf("unsupported double-array format version: %d", -v)
+	}
+	flags := rr.readInt()
+	if rr.err != nil {
+		return nil, rr.err
+	}
+	if flags&^flagByteLabels != 0 {
+		return nil, fmt.Errorf("unknown format flags: %#x", flags&^flagByteLabels)
+	}
+
+	// read labels.
+	n, err := rr.readSize()
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]rune, n)
+	for i := range labels {
+		labels[i] = rr.readRune()
+	}
+
+	// read slots.
+	n, err = rr.readSize()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errors.New("no root node")
+	}
+	da := &DATree{ByteLabels: flags&flagByteLabels != 0}
+	da.setLabels(labels)
+	da.extend(n)
+	for x := range da.check {
+		da.base[x] = rr.readInt()
+		da.check[x] = rr.readInt()
+		da.fail[x] = rr.readInt()
+		da.output[x] = rr.readInt()
+		da.edges[x] = rr.readInt()
+	}
+
+	if err := da.fillDepths(); err != nil {
+		return nil, err
+	}
+
+	// read levels.
+	n, err = rr.readSize()
+	if err != nil {
+		return nil, err
+	}
+	da.levels = make([]int, n)
+	for i := range da.levels {
+		da.levels[i] = rr.readInt()
+	}
+	if rr.err != nil {
+		return nil, rr.err
+	}
+	return da, nil
+}
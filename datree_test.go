@@ -0,0 +1,97 @@
+package trietree_test
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+func TestDATree(t *testing.T) {
+	keys := []string{"ab", "bc", "bab", "d", "abcde", "あい", "い", "あいう", "xyz"}
+	dt := testDTreePut(t, &trietree.DTree{}, keys...)
+	st := trietree.Freeze(dt)
+	da0 := trietree.FreezeDA(dt)
+	b := &bytes.Buffer{}
+	if err := da0.Write(b); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	da1, err := trietree.ReadDA(b)
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+
+	for _, da := range []*trietree.DATree{da0, da1} {
+		for _, q := range []string{"", "abcde", "babab", "xあいうい", "zxyzab", "あ"} {
+			want := slices.Collect(st.Predict(q))
+			if d := cmp.Diff(want, slices.Collect(da.Predict(q))); d != "" {
+				t.Errorf("unexpected Predict for %q: -want +got\n%s", q, d)
+			}
+			var wantReports, gotReports reports
+			st.Scan(q, &wantReports)
+			da.Scan(q, &gotReports)
+			gotReports.compare(t, wantReports)
+
+			wantPrefix, wantID := st.LongestPrefix(q)
+			gotPrefix, gotID := da.LongestPrefix(q)
+			if gotPrefix != wantPrefix || gotID != wantID {
+				t.Errorf("unexpected LongestPrefix for %q: want=(%q, %d) got=(%q, %d)", q, wantPrefix, wantID, gotPrefix, gotID)
+			}
+		}
+		for i, k := range keys {
+			if id, ok := da.Get(k); !ok || id != i+1 {
+				t.Errorf("unexpected Get for %q: %d %t", k, id, ok)
+			}
+		}
+		for _, k := range []string{"", "a", "abc", "あいうえ", "z"} {
+			if id, ok := da.Get(k); ok {
+				t.Errorf("unexpected Get for %q: %d", k, id)
+			}
+		}
+	}
+}
+
+func TestDATree_random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randString := func(n int) string {
+		rs := make([]rune, 1+rnd.Intn(n))
+		for i := range rs {
+			rs[i] = []rune("abcあいう")[rnd.Intn(6)]
+		}
+		return string(rs)
+	}
+	for range 20 {
+		var keys []string
+		dt := &trietree.DTree{}
+		for range 50 {
+			k := randString(6)
+			if slices.Contains(keys, k) {
+				continue
+			}
+			keys = append(keys, k)
+			dt.Put(k)
+		}
+		st := trietree.Freeze(dt)
+		da := trietree.FreezeDA(dt)
+		for range 10 {
+			q := randString(30)
+			want := slices.Collect(st.Predict(q))
+			if d := cmp.Diff(want, slices.Collect(da.Predict(q))); d != "" {
+				t.Fatalf("unexpected Predict for %q with %q: -want +got\n%s", q, keys, d)
+			}
+		}
+	}
+}
+
+func TestReadDA_invalid(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := trietree.Freeze(&trietree.DTree{}).Write(b); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	if _, err := trietree.ReadDA(b); err == nil {
+		t.Fatal("unexpected success to read STree as DATree")
+	}
+}
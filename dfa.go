@@ -0,0 +1,65 @@
+package trietree
+
+import "math"
+
+// dfa is a table of transitions of the automaton for all nodes and labels.
+// Labels are mapped to classes to reduce the table.  Labels which don't appear
+// in keys are mapped to the class 0, which always transits to the root.
+type dfa struct {
+	classes *codeMap
+	// next is the table of transitions, indexed by node*classes.n+class-1.
+	next []int32
+}
+
+// CompileDFA precomputes transitions of the automaton for all nodes and
+// labels in keys.  After that, Scan, Predict and others look up the table
+// once per label, instead of searching children and following failure links.
+// The table takes (number of nodes) * (number of distinct labels) * 4 bytes.
+// When it exceeds limit bytes, CompileDFA returns false, and the tree keeps
+// working without the table.
+//
+// The table is not serialized, so compile it again after Read.  It must not
+// be called concurrently with other methods.
+func (st *STree) CompileDFA(limit int) bool {
+	if len(st.Nodes) == 0 {
+		return false
+	}
+	classes := newCodeMap()
+	for _, n := range st.Nodes[1:] {
+		classes.add(n.Label)
+	}
+	k := classes.n
+	if len(st.Nodes) > math.MaxInt32 || k > 0 && len(st.Nodes) > limit/4/k {
+		return false
+	}
+	d := &dfa{
+		classes: classes,
+		next:    make([]int32, len(st.Nodes)*k),
+	}
+	// fill transitions in breadth first order, as those depend on
+	// transitions of failure nodes.
+	queue := []int{0}
+	for len(queue) > 0 {
+		x := queue[0]
+		queue = queue[1:]
+		n := &st.Nodes[x]
+		row := d.next[x*k : (x+1)*k]
+		if x > 0 {
+			copy(row, d.next[n.Fail*k:(n.Fail+1)*k])
+		}
+		for i := n.Start; i < n.End; i++ {
+			row[classes.code(st.Nodes[i].Label)-1] = int32(i)
+			queue = append(queue, i)
+		}
+	}
+	st.dfa = d
+	return true
+}
+
+func (d *dfa) nextNode(x int, r rune) int {
+	c := d.classes.code(r)
+	if c == 0 {
+		return 0
+	}
+	return int(d.next[x*d.classes.n+c-1])
+}
@@ -0,0 +1,48 @@
+package trietree_test
+
+import (
+	"slices"
+	"testing"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+func TestSTree_CompileDFA(t *testing.T) {
+	keys := []string{"ab", "bc", "bab", "d", "abcde", "あい", "い", "Kelvin"}
+	dt := testDTreePut(t, &trietree.DTree{Normalizer: unicode.ToLower}, keys...)
+	st0 := trietree.Freeze(dt)
+	st1 := trietree.Freeze(dt)
+	if !st1.CompileDFA(1 << 20) {
+		t.Fatal("failed to compile DFA")
+	}
+	for _, q := range []string{"", "xyz", "abcde", "babab", "あいいd", "KELVIN", "zabあxbcい"} {
+		want := slices.Collect(st0.Predict(q))
+		if d := cmp.Diff(want, slices.Collect(st1.Predict(q))); d != "" {
+			t.Errorf("unexpected Predict for %q: -want +got\n%s", q, d)
+		}
+		var wantReports, gotReports reports
+		st0.Scan(q, &wantReports)
+		st1.Scan(q, &gotReports)
+		gotReports.compare(t, wantReports)
+		wantCounts := make([]uint32, len(keys))
+		gotCounts := make([]uint32, len(keys))
+		st0.Count(q, wantCounts)
+		st1.Count(q, gotCounts)
+		if d := cmp.Diff(wantCounts, gotCounts); d != "" {
+			t.Errorf("unexpected Count for %q: -want +got\n%s", q, d)
+		}
+	}
+
+	// fall back when the table exceeds the limit.
+	st2 := trietree.Freeze(dt)
+	if st2.CompileDFA(100) {
+		t.Fatal("unexpected success to compile DFA over limit")
+	}
+	want := []prediction{
+		{Start: 1, End: 3, ID: 1, Key: "ab"},
+		{Start: 2, End: 4, ID: 2, Key: "bc"},
+	}
+	testPredict(t, st2, "xabc", want)
+}
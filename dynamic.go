@@ -2,14 +2,35 @@ package trietree
 
 import (
 	"context"
-	"unicode/utf8"
+	"slices"
 )
 
 // DTree is dynamic tree.
+//
+// Failure links for the Aho-Corasick algorithm are rebuilt automatically by
+// the first scan after modification, like Put and Delete.  So the first scan
+// after modification is not safe for concurrent use.  Call FillFailure after
+// modification, then following scans can be called concurrently.
 type DTree struct {
 	Root DNode
 
+	// Normalizer is applied to keys and queries when not nil.  It should be
+	// set before putting any keys.
+	Normalizer Normalizer
+
+	// ByteLabels makes each byte of keys and queries a label, instead of
+	// each rune decoded from UTF-8.  Labels are still passed as rune in APIs.
+	// It should be set before putting any keys.
+	ByteLabels bool
+
 	lastEdgeID int
+
+	// edges is an index to find edge nodes from its ID.
+	edges []*DNode
+
+	// failureStale indicates Failure fields are not maintained after the tree
+	// was modified.  Those will be rebuilt by ensureFailure().
+	failureStale bool
 }
 
 // DNode is a node of dynamic tree.
@@ -21,7 +42,8 @@ type DNode struct {
 	// EdgeID indicates the node has a corresponding key or not.
 	EdgeID int
 
-	// Level is equals key length when EdgeID is not zero.
+	// Level is the depth of the node, so it equals key length when EdgeID is
+	// not zero.
 	Level int
 
 	// Low is sibling nodes which have smaller Label.
@@ -34,8 +56,16 @@ type DNode struct {
 	Child *DNode
 
 	// Failure is used as a search destination when the desired Label is not
-	// found in Child. This will be filled by FillFailure().
+	// found in Child. This will be filled by FillFailure(), or automatically
+	// before scanning.
 	Failure *DNode
+
+	// Output is the nearest node which has EdgeID in the chain of Failure.
+	// It is nil when there are no such nodes.  This will be filled with
+	// Failure.
+	Output *DNode
+
+	parent *DNode
 }
 
 // dig searches for a node with the desired label among its sibling nodes, or
@@ -43,7 +73,7 @@ type DNode struct {
 func (dn *DNode) dig(c rune) *DNode {
 	p := dn.Child
 	if p == nil {
-		dn.Child = &DNode{Label: c}
+		dn.Child = &DNode{Label: c, Level: dn.Level + 1, parent: dn}
 		return dn.Child
 	}
 	for {
@@ -52,13 +82,13 @@ func (dn *DNode) dig(c rune) *DNode {
 		}
 		if c < p.Label {
 			if p.Low == nil {
-				p.Low = &DNode{Label: c}
+				p.Low = &DNode{Label: c, Level: dn.Level + 1, parent: dn}
 				return p.Low
 			}
 			p = p.Low
 		} else {
 			if p.High == nil {
-				p.High = &DNode{Label: c}
+				p.High = &DNode{Label: c, Level: dn.Level + 1, parent: dn}
 				return p.High
 			}
 			p = p.High
@@ -87,18 +117,71 @@ func (dn *DNode) Get(r rune) *DNode {
 func (dt *DTree) Put(k string) int {
 	n := &dt.Root
 	level := 0
-	for _, r := range k {
-		n = n.dig(r)
+	for i := 0; i < len(k); {
+		r, sz := decodeLabel(k[i:], dt.ByteLabels)
+		n = n.dig(dt.normalize(r))
+		i += sz
 		level++
 	}
 	if n.EdgeID <= 0 {
 		dt.lastEdgeID++
 		n.EdgeID = dt.lastEdgeID
+		dt.edges = append(dt.edges, n)
+		dt.failureStale = true
 	}
 	n.Level = level
 	return n.EdgeID
 }
 
+// Delete removes an edge for k key and returns its ID.
+// Nodes which become unnecessary are pruned from the tree.
+func (dt *DTree) Delete(k string) (edgeID int, ok bool) {
+	n := dt.Get(k)
+	if n == nil || n.EdgeID <= 0 {
+		return 0, false
+	}
+	edgeID = n.EdgeID
+	dt.edges[edgeID-1] = nil
+	n.EdgeID = 0
+	// prune childless nodes.
+	for n.parent != nil && n.Child == nil && n.EdgeID <= 0 {
+		p := n.parent
+		p.unlink(n)
+		n = p
+	}
+	dt.failureStale = true
+	return edgeID, true
+}
+
+// unlink removes a child node c from children of dn.
+func (dn *DNode) unlink(c *DNode) {
+	pp := &dn.Child
+	for *pp != c {
+		if c.Label < (*pp).Label {
+			pp = &(*pp).Low
+		} else {
+			pp = &(*pp).High
+		}
+	}
+	switch {
+	case c.Low == nil:
+		*pp = c.High
+	case c.High == nil:
+		*pp = c.Low
+	default:
+		// replace c with the minimum node among c.High.
+		sp := &c.High
+		for (*sp).Low != nil {
+			sp = &(*sp).Low
+		}
+		s := *sp
+		*sp = s.High
+		s.Low, s.High = c.Low, c.High
+		*pp = s
+	}
+	c.Low, c.High = nil, nil
+}
+
 // Scan scans a string to find matched words.
 func (dt *DTree) Scan(s string, r ScanReporter) error {
 	return dt.ScanContext(context.Background(), s, r)
@@ -107,25 +190,28 @@ func (dt *DTree) Scan(s string, r ScanReporter) error {
 // ScanContext scans a string to find matched words.
 // ScanReporter r will receive reports for each characters when scan.
 func (dt *DTree) ScanContext(ctx context.Context, s string, r ScanReporter) error {
-	sr := newScanReport(r, len(s))
-	curr := &dt.Root
-	for i, c := range s {
-		next := dt.nextNode(curr, c)
-		// emit a scan event.
-		sr.reset(i, c)
-		for n := next; n != nil; n = n.Failure {
-			if n.EdgeID > 0 {
-				sr.add(n.EdgeID, n.Level)
-			}
-		}
-		sr.emit()
-		// prepare for next.
-		if err := ctx.Err(); err != nil {
-			return err
-		}
-		curr = next
+	return dt.ScanWith(ctx, s, MatchOptions{}, r)
+}
+
+// ScanWith is same as ScanContext, but reports only matches which are
+// specified by opts.
+func (dt *DTree) ScanWith(ctx context.Context, s string, opts MatchOptions, r ScanReporter) error {
+	dt.ensureFailure()
+	return scanContext[*DNode](ctx, dt, s, r, opts)
+}
+
+// ScanBytes scans a byte slice to find matched words.
+func (dt *DTree) ScanBytes(b []byte, r ScanReporter) error {
+	dt.ensureFailure()
+	return scanContext[*DNode](context.Background(), dt, b, r, MatchOptions{})
+}
+
+// normalize applies Normalizer to a rune.
+func (dt *DTree) normalize(r rune) rune {
+	if dt.Normalizer == nil {
+		return r
 	}
-	return nil
+	return dt.Normalizer(r)
 }
 
 func (dt *DTree) nextNode(curr *DNode, c rune) *DNode {
@@ -160,37 +246,74 @@ func (dn *DNode) eachSiblings(fn procDNode) {
 // Get retrieve a node for key, otherwise returns nil.
 func (dt *DTree) Get(k string) *DNode {
 	n := &dt.Root
-	for _, r := range k {
-		n = n.Get(r)
+	for i := 0; i < len(k); {
+		r, sz := decodeLabel(k[i:], dt.ByteLabels)
+		n = n.Get(dt.normalize(r))
 		if n == nil {
 			return nil
 		}
+		i += sz
 	}
 	return n
 }
 
-// FillFailure fill Failure field with Aho-Corasick algorithm.
+// Key returns a key string for the edge ID.
+// ok will be false when no edges have the ID.
+func (dt *DTree) Key(id int) (k string, ok bool) {
+	if id <= 0 || id > len(dt.edges) {
+		return "", false
+	}
+	n := dt.edges[id-1]
+	if n == nil || n.EdgeID != id {
+		return "", false
+	}
+	var rs []rune
+	for ; n.parent != nil; n = n.parent {
+		rs = append(rs, n.Label)
+	}
+	var b []byte
+	for _, r := range slices.Backward(rs) {
+		b = appendLabel(b, r, dt.ByteLabels)
+	}
+	return string(b), true
+}
+
+// FillFailure fill Failure and Output fields with Aho-Corasick algorithm.
+// Scanning methods fill those fields automatically when the tree was
+// modified, but call this explicitly before scanning the tree concurrently.
 func (dt *DTree) FillFailure() {
-	dt.Root.Failure = &dt.Root
-	dt.fillFailure(&dt.Root)
-	dt.Root.Failure = nil
+	root := &dt.Root
+	root.Failure = root
+	// fill Failure in breadth first order, as it depends on Failure of
+	// shallower nodes.
+	queue := []*DNode{root}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		pf := parent.Failure
+		parent.Child.eachSiblings(func(curr *DNode) {
+			f := dt.nextNode(pf, curr.Label)
+			if f == curr {
+				f = root
+			}
+			curr.Failure = f
+			if f.EdgeID > 0 {
+				curr.Output = f
+			} else {
+				curr.Output = f.Output
+			}
+			queue = append(queue, curr)
+		})
+	}
+	root.Failure = nil
+	dt.failureStale = false
 }
 
-func (dt *DTree) fillFailure(parent *DNode) {
-	if parent.Child == nil {
-		return
+// ensureFailure fills Failure fields when those are stale.
+func (dt *DTree) ensureFailure() {
+	if dt.failureStale {
+		dt.FillFailure()
 	}
-	//fmt.Printf("fillFailure: parent(%p)=%+[1]v\n", parent)
-	pf := parent.Failure
-	parent.Child.eachSiblings(func(curr *DNode) {
-		f := dt.nextNode(pf, curr.Label)
-		if f == curr {
-			f = &dt.Root
-		}
-		curr.Failure = f
-		//fmt.Printf("  curr(%p)=%+[1]v\n", curr)
-		dt.fillFailure(curr)
-	})
 }
 
 // CountChild counts child nodes.
@@ -212,21 +335,23 @@ func (dn *DNode) CountAll() int {
 // LongestPrefix finds a longest prefix node/edge matches given s string.
 func (dt *DTree) LongestPrefix(s string) (prefix string, edgeID int) {
 	var last *DNode
-	ilast := 0
+	end := 0
 	curr := &dt.Root
-	for i, r := range s {
-		next := curr.Get(r)
+	for i := 0; i < len(s); {
+		r, sz := decodeLabel(s[i:], dt.ByteLabels)
+		next := curr.Get(dt.normalize(r))
 		if next == nil {
 			break
 		}
+		i += sz
 		if next.EdgeID > 0 {
 			last = next
-			ilast = i
+			end = i
 		}
 		curr = next
 	}
 	if last == nil {
 		return "", 0
 	}
-	return s[:ilast+utf8.RuneLen(last.Label)], last.EdgeID
+	return s[:end], last.EdgeID
 }
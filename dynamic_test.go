@@ -1,7 +1,9 @@
 package trietree_test
 
 import (
+	"context"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -182,3 +184,137 @@ func TestDTree_ScanMultiple(t *testing.T) {
 		{2, 'd', []node{{4, 1}}},
 	})
 }
+
+func TestDTree_Key(t *testing.T) {
+	keys := []string{"ab", "bc", "bab", "d", "abcde", "あいう"}
+	dt := testDTreePut(t, &trietree.DTree{}, keys...)
+	for i, want := range keys {
+		got, ok := dt.Key(i + 1)
+		if !ok {
+			t.Errorf("key for ID=%d not found", i+1)
+			continue
+		}
+		if got != want {
+			t.Errorf("unexpected key for ID=%d: want=%q got=%q", i+1, want, got)
+		}
+	}
+	for _, id := range []int{-1, 0, len(keys) + 1} {
+		if got, ok := dt.Key(id); ok {
+			t.Errorf("unexpected key for ID=%d: %q", id, got)
+		}
+	}
+}
+
+func TestDTree_Delete(t *testing.T) {
+	dt := testDTreePut(t, &trietree.DTree{}, "d", "b", "f", "a", "c", "e", "g", "ab", "abc", "bab")
+	for i, c := range []struct {
+		key    string
+		wantID int
+		wantOK bool
+	}{
+		{"d", 1, true},
+		{"d", 0, false},
+		{"abc", 9, true},
+		{"b", 2, true},
+		{"ba", 0, false},
+		{"zzz", 0, false},
+	} {
+		gotID, gotOK := dt.Delete(c.key)
+		if gotID != c.wantID || gotOK != c.wantOK {
+			t.Errorf("unexpected #%d %q: want=(%d, %t) got=(%d, %t)", i, c.key, c.wantID, c.wantOK, gotID, gotOK)
+		}
+	}
+	want := []keyID{
+		{"a", 4}, {"ab", 8}, {"bab", 10}, {"c", 5}, {"e", 6}, {"f", 3}, {"g", 7},
+	}
+	if d := cmp.Diff(want, collectKeyIDs(dt.All())); d != "" {
+		t.Errorf("unexpected keys: -want +got\n%s", d)
+	}
+	// "d" and "abc" nodes should be pruned, "b" should be kept.
+	if n := dt.Root.CountAll(); n != 10 {
+		t.Errorf("CountAll()=%d unexpected (expected:10)", n)
+	}
+	if k, ok := dt.Key(1); ok {
+		t.Errorf("unexpected key for deleted ID: %q", k)
+	}
+
+	dt.FillFailure()
+	testDTreeScan(t, dt, "babc", reports{
+		{0, 'b', nil},
+		{1, 'a', []node{{4, 1}}},
+		{2, 'b', []node{{10, 3}, {8, 2}}},
+		{3, 'c', []node{{5, 1}}},
+	})
+
+	// put a deleted key again, it gets a new ID.
+	if id := dt.Put("d"); id != 11 {
+		t.Errorf("unexpected ID for re-put key: %d", id)
+	}
+}
+
+func TestDTree_autoFailure(t *testing.T) {
+	dt := &trietree.DTree{}
+	dt.Put("ab")
+	dt.Put("bab")
+	// scan without FillFailure.
+	testDTreeScan(t, dt, "bab", reports{
+		{0, 'b', nil},
+		{1, 'a', nil},
+		{2, 'b', []node{{2, 3}, {1, 2}}},
+	})
+	// put after failures are filled.
+	dt.Put("b")
+	testDTreeScan(t, dt, "bab", reports{
+		{0, 'b', []node{{3, 1}}},
+		{1, 'a', nil},
+		{2, 'b', []node{{2, 3}, {1, 2}, {3, 1}}},
+	})
+	// delete after failures are filled.
+	dt.Delete("ab")
+	testDTreeScan(t, dt, "bab", reports{
+		{0, 'b', []node{{3, 1}}},
+		{1, 'a', nil},
+		{2, 'b', []node{{2, 3}, {3, 1}}},
+	})
+}
+
+func TestDTree_concurrentScan(t *testing.T) {
+	dt := &trietree.DTree{}
+	dt.Put("ab")
+	dt.Put("bab")
+	// fill failures before concurrent scans.
+	dt.FillFailure()
+	want := reports{
+		{0, 'b', nil},
+		{1, 'a', nil},
+		{2, 'b', []node{{2, 3}, {1, 2}}},
+	}
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var got reports
+			if err := dt.ScanContext(context.Background(), "bab", &got); err != nil {
+				t.Errorf("scan is failed: %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected reports: got=%+v want=%+v", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDTree_deepFailure(t *testing.T) {
+	// Failure of "abcd" depends on Failure of "bc", which is filled later
+	// than "abcd" in depth first order.
+	dt := testDTreePut(t, &trietree.DTree{}, "abcd", "bc", "cd")
+	testDTreeScan(t, dt, "abcd", reports{
+		{0, 'a', nil},
+		{1, 'b', nil},
+		{2, 'c', []node{{2, 2}}},
+		{3, 'd', []node{{1, 4}, {3, 2}}},
+	})
+}
@@ -0,0 +1,70 @@
+package trietree
+
+// FindAllStringIndex returns index pairs of successive non-overlapping
+// matches of keys in s, like regexp.Regexp.FindAllStringIndex.  Matches are
+// found with LeftmostFirst.  If n >= 0, it returns at most n matches.  It
+// returns nil when no matches.
+func (dt *DTree) FindAllStringIndex(s string, n int) [][]int {
+	dt.ensureFailure()
+	return findAllIndex[*DNode](dt, s, n)
+}
+
+// FindAllStringIndex returns index pairs of successive non-overlapping
+// matches of keys in s, like regexp.Regexp.FindAllStringIndex.  Matches are
+// found with LeftmostFirst.  If n >= 0, it returns at most n matches.  It
+// returns nil when no matches.
+func (st *STree) FindAllStringIndex(s string, n int) [][]int {
+	return findAllIndex[int](st.indexed(), s, n)
+}
+
+// FindAllString returns successive non-overlapping matches of keys in s, like
+// regexp.Regexp.FindAllString.  See FindAllStringIndex for details.
+func (dt *DTree) FindAllString(s string, n int) []string {
+	return findAllString(s, dt.FindAllStringIndex(s, n))
+}
+
+// FindAllString returns successive non-overlapping matches of keys in s, like
+// regexp.Regexp.FindAllString.  See FindAllStringIndex for details.
+func (st *STree) FindAllString(s string, n int) []string {
+	return findAllString(s, st.FindAllStringIndex(s, n))
+}
+
+// FindAllIndex returns index pairs of successive non-overlapping matches of
+// keys in b, like regexp.Regexp.FindAllIndex.  See FindAllStringIndex for
+// details.
+func (dt *DTree) FindAllIndex(b []byte, n int) [][]int {
+	dt.ensureFailure()
+	return findAllIndex[*DNode](dt, b, n)
+}
+
+// FindAllIndex returns index pairs of successive non-overlapping matches of
+// keys in b, like regexp.Regexp.FindAllIndex.  See FindAllStringIndex for
+// details.
+func (st *STree) FindAllIndex(b []byte, n int) [][]int {
+	return findAllIndex[int](st.indexed(), b, n)
+}
+
+func findAllIndex[T comparable, S byteSeq](tree predictableTree[T], s S, n int) [][]int {
+	if n == 0 {
+		return nil
+	}
+	var matches [][]int
+	for p := range newLeftmostFinder(tree, s, MatchOptions{Kind: LeftmostFirst}).all() {
+		matches = append(matches, []int{p.Start, p.End})
+		if len(matches) == n {
+			break
+		}
+	}
+	return matches
+}
+
+func findAllString(s string, matches [][]int) []string {
+	if matches == nil {
+		return nil
+	}
+	strs := make([]string, len(matches))
+	for i, m := range matches {
+		strs[i] = s[m[0]:m[1]]
+	}
+	return strs
+}
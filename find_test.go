@@ -0,0 +1,45 @@
+package trietree_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+type allFinder interface {
+	FindAllStringIndex(string, int) [][]int
+	FindAllString(string, int) []string
+	FindAllIndex([]byte, int) [][]int
+}
+
+func TestFindAll(t *testing.T) {
+	keys := []string{"ab", "abcd", "c", "あい", "い"}
+	// an alternation of regexp is leftmost-first.
+	re := regexp.MustCompile("ab|abcd|c|あい|い")
+	dt := testDTreePut(t, &trietree.DTree{}, keys...)
+	for _, tree := range []allFinder{dt, trietree.Freeze(dt)} {
+		for _, s := range []string{"", "xyz", "abcdc", "ababab", "あいいうc"} {
+			for _, n := range []int{-1, 0, 1, 2, 10} {
+				if d := cmp.Diff(re.FindAllStringIndex(s, n), tree.FindAllStringIndex(s, n)); d != "" {
+					t.Errorf("unexpected FindAllStringIndex(%q, %d) for %T: -want +got\n%s", s, n, tree, d)
+				}
+				if d := cmp.Diff(re.FindAllString(s, n), tree.FindAllString(s, n)); d != "" {
+					t.Errorf("unexpected FindAllString(%q, %d) for %T: -want +got\n%s", s, n, tree, d)
+				}
+				if d := cmp.Diff(re.FindAllIndex([]byte(s), n), tree.FindAllIndex([]byte(s), n)); d != "" {
+					t.Errorf("unexpected FindAllIndex(%q, %d) for %T: -want +got\n%s", s, n, tree, d)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkFindAllStringIndex_longKey(b *testing.B) {
+	st := trietree.Freeze(newLongKeyTree())
+	b.ResetTimer()
+	for range b.N {
+		st.FindAllStringIndex(longKeyQuery, -1)
+	}
+}
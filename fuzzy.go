@@ -0,0 +1,99 @@
+package trietree
+
+import (
+	"iter"
+)
+
+// FuzzyMatch is a key which found by FuzzySearch.
+type FuzzyMatch struct {
+	Key      string // Key is the found key.
+	ID       int    // ID is for edge node identifier.
+	Distance int    // Distance is Levenshtein distance between Key and query.
+}
+
+// FuzzySearch returns an iterator which enumerates keys within Levenshtein
+// distance k from q.  Keys are enumerated in lexicographic order.
+func (dt *DTree) FuzzySearch(q string, k int) iter.Seq[FuzzyMatch] {
+	return fuzzySearch[*DNode](dt, q, k)
+}
+
+// FuzzySearch returns an iterator which enumerates keys within Levenshtein
+// distance k from q.  Keys are enumerated in lexicographic order.
+func (st *STree) FuzzySearch(q string, k int) iter.Seq[FuzzyMatch] {
+	return fuzzySearch[int](st, q, k)
+}
+
+func fuzzySearch[T comparable](tree searchableTree[T], q string, k int) iter.Seq[FuzzyMatch] {
+	return func(yield func(FuzzyMatch) bool) {
+		if k < 0 {
+			return
+		}
+		fw := fuzzyWalker[T]{
+			tree:  tree,
+			query: decodeLabels(q, tree.byteLabels()),
+			limit: k,
+			yield: yield,
+		}
+		for i, r := range fw.query {
+			fw.query[i] = tree.normalize(r)
+		}
+		row := make([]int, len(fw.query)+1)
+		for i := range row {
+			row[i] = i
+		}
+		fw.rows = append(fw.rows, row)
+		node := tree.root()
+		if id := tree.nodeId(node); id > 0 && row[len(row)-1] <= k {
+			if !yield(FuzzyMatch{Key: "", ID: id, Distance: row[len(row)-1]}) {
+				return
+			}
+		}
+		fw.walk(node, make([]byte, 0, 16), 0)
+	}
+}
+
+type fuzzyWalker[T comparable] struct {
+	tree  searchableTree[T]
+	query []rune
+	limit int
+	yield func(FuzzyMatch) bool
+
+	// rows holds rows of Levenshtein distance table for each depth.
+	rows [][]int
+}
+
+// walk visits children of the node at depth d.  This returns false when the
+// search should stop.
+func (fw *fuzzyWalker[T]) walk(node T, buf []byte, d int) bool {
+	prev := fw.rows[d]
+	if d+1 >= len(fw.rows) {
+		fw.rows = append(fw.rows, make([]int, len(prev)))
+	}
+	row := fw.rows[d+1]
+	for r, child := range fw.tree.children(node) {
+		// compute a next row of the table, and its minimum.
+		row[0] = prev[0] + 1
+		least := row[0]
+		for j, c := range fw.query {
+			cost := 1
+			if c == r {
+				cost = 0
+			}
+			row[j+1] = min(prev[j+1]+1, row[j]+1, prev[j]+cost)
+			least = min(least, row[j+1])
+		}
+		if least > fw.limit {
+			continue
+		}
+		key := appendLabel(buf, r, fw.tree.byteLabels())
+		if id := fw.tree.nodeId(child); id > 0 && row[len(row)-1] <= fw.limit {
+			if !fw.yield(FuzzyMatch{Key: string(key), ID: id, Distance: row[len(row)-1]}) {
+				return false
+			}
+		}
+		if !fw.walk(child, key, d+1) {
+			return false
+		}
+	}
+	return true
+}
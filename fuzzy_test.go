@@ -0,0 +1,83 @@
+package trietree_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range ra {
+		curr[0] = i + 1
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			curr[j+1] = min(prev[j+1]+1, curr[j]+1, prev[j]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+type fuzzySearcher interface {
+	FuzzySearch(q string, k int) iter.Seq[trietree.FuzzyMatch]
+}
+
+func testFuzzySearch(t *testing.T, fs fuzzySearcher, keys []string) {
+	t.Helper()
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	for _, q := range []string{"", "a", "ab", "abc", "bac", "cat", "kitten", "sitting", "あい", "あう"} {
+		for k := -1; k <= 3; k++ {
+			want := []trietree.FuzzyMatch{}
+			for _, key := range sorted {
+				if d := levenshtein(key, q); d <= k {
+					want = append(want, trietree.FuzzyMatch{
+						Key:      key,
+						ID:       slices.Index(keys, key) + 1,
+						Distance: d,
+					})
+				}
+			}
+			got := []trietree.FuzzyMatch{}
+			for m := range fs.FuzzySearch(q, k) {
+				got = append(got, m)
+			}
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("unexpected matches for q=%q k=%d: -want +got\n%s", q, k, d)
+			}
+		}
+	}
+}
+
+func TestFuzzySearch(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "b", "bcd", "cat", "cut", "kitten", "sitting", "あ", "あい", "abd"}
+	t.Run("dynamic", func(t *testing.T) {
+		testFuzzySearch(t, testDTreePut(t, &trietree.DTree{}, keys...), keys)
+	})
+	t.Run("static", func(t *testing.T) {
+		testFuzzySearch(t, trietree.Freeze(testDTreePut(t, &trietree.DTree{}, keys...)), keys)
+	})
+	t.Run("normalized", func(t *testing.T) {
+		dt := testDTreePut(t, &trietree.DTree{Normalizer: unicode.ToLower}, "Kelvin", "cat")
+		for _, fs := range []fuzzySearcher{dt, trietree.Freeze(dt)} {
+			want := []trietree.FuzzyMatch{{Key: "cat", ID: 2, Distance: 0}}
+			got := slices.Collect(fs.FuzzySearch("CAT", 0))
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("unexpected matches for %T: -want +got\n%s", fs, d)
+			}
+		}
+	})
+}
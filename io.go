@@ -3,7 +3,9 @@ package trietree
 import (
 	"bufio"
 	"encoding/binary"
+	"fmt"
 	"io"
+	"math"
 )
 
 type writer struct {
@@ -92,3 +94,15 @@ func (r *reader) readInt64() (int64, error) {
 	}
 	return n, nil
 }
+
+// readSize reads a size of an array.
+func (r *reader) readSize() (int, error) {
+	n, err := r.readInt64()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || n > math.MaxInt32 && intSize == 32 {
+		return 0, fmt.Errorf("invalid size: %d", n)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,94 @@
+package trietree
+
+import "unicode/utf8"
+
+// byteSeq is a constraint for types of queries.
+type byteSeq interface {
+	string | []byte
+}
+
+// decodeLabel decodes the first label in s, and returns it with its size in
+// bytes.  A label is a byte when byteLabels is true, otherwise a rune.
+// s must not be empty.
+func decodeLabel[S byteSeq](s S, byteLabels bool) (rune, int) {
+	if byteLabels || s[0] < utf8.RuneSelf {
+		return rune(s[0]), 1
+	}
+	switch v := any(s).(type) {
+	case string:
+		return utf8.DecodeRuneInString(v)
+	case []byte:
+		return utf8.DecodeRune(v)
+	}
+	panic("unreachable")
+}
+
+// decodeLastLabel decodes the last label in s, and returns it with its size
+// in bytes.  s must not be empty.
+func decodeLastLabel[S byteSeq](s S, byteLabels bool) (rune, int) {
+	if byteLabels || s[len(s)-1] < utf8.RuneSelf {
+		return rune(s[len(s)-1]), 1
+	}
+	switch v := any(s).(type) {
+	case string:
+		return utf8.DecodeLastRuneInString(v)
+	case []byte:
+		return utf8.DecodeLastRune(v)
+	}
+	panic("unreachable")
+}
+
+// decodeLabels decodes all labels in s.
+func decodeLabels(s string, byteLabels bool) []rune {
+	if byteLabels {
+		labels := make([]rune, len(s))
+		for i := 0; i < len(s); i++ {
+			labels[i] = rune(s[i])
+		}
+		return labels
+	}
+	return []rune(s)
+}
+
+// appendLabel appends a label to buf, and returns the extended buffer.
+func appendLabel(buf []byte, r rune, byteLabels bool) []byte {
+	if byteLabels {
+		return append(buf, byte(r))
+	}
+	return utf8.AppendRune(buf, r)
+}
+
+// codeMap maps labels to dense codes, which start from 1.  Code 0 is for
+// unknown labels.
+type codeMap struct {
+	n int
+	// low is codes for labels less than 256.
+	low [256]int32
+	// high is codes for other labels.
+	high map[rune]int32
+}
+
+func newCodeMap() *codeMap {
+	return &codeMap{high: map[rune]int32{}}
+}
+
+// add assigns a new code to label r, if r has no codes yet.
+func (m *codeMap) add(r rune) {
+	if m.code(r) > 0 {
+		return
+	}
+	m.n++
+	if r >= 0 && r < 256 {
+		m.low[r] = int32(m.n)
+		return
+	}
+	m.high[r] = int32(m.n)
+}
+
+// code returns a code of label r.  It returns 0 for unknown labels.
+func (m *codeMap) code(r rune) int {
+	if r >= 0 && r < 256 {
+		return int(m.low[r])
+	}
+	return int(m.high[r])
+}
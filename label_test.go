@@ -0,0 +1,77 @@
+package trietree_test
+
+import (
+	"bytes"
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+func TestByteLabels(t *testing.T) {
+	dt := &trietree.DTree{ByteLabels: true}
+	// keys are not valid UTF-8.
+	testDTreePut(t, dt, "\xff\xfe", "\xfeab", "\xe3\x81")
+	st0 := trietree.Freeze(dt)
+	b := &bytes.Buffer{}
+	if err := st0.Write(b); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	st1, err := trietree.Read(b)
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	if !st1.ByteLabels {
+		t.Fatal("ByteLabels is not restored by Read")
+	}
+
+	// "\xe3\x81\x82" is "あ" in UTF-8, it is split into bytes.
+	q := []byte("x\xff\xfeab\xe3\x81\x82")
+	want := []prediction{
+		{Start: 1, End: 3, ID: 1},
+		{Start: 2, End: 5, ID: 2},
+		{Start: 5, End: 7, ID: 3},
+	}
+	for _, tree := range []interface {
+		PredictBytes([]byte) iter.Seq[trietree.Prediction]
+		Key(int) (string, bool)
+	}{
+		dt, st0, st1,
+	} {
+		var got []prediction
+		for p := range tree.PredictBytes(q) {
+			got = append(got, prediction{Start: p.Start, End: p.End, ID: p.ID})
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("unexpected PredictBytes for %T: -want +got\n%s", tree, d)
+		}
+		if k, ok := tree.Key(2); !ok || k != "\xfeab" {
+			t.Errorf("unexpected Key for %T: %q %t", tree, k, ok)
+		}
+	}
+
+	exp := reports{
+		{0, 0xff, nil},
+		{1, 0xfe, []node{{1, 2}}},
+		{2, 'a', nil},
+		{3, 'b', []node{{2, 3}}},
+	}
+	var act reports
+	if err := dt.ScanBytes([]byte("\xff\xfeab"), &act); err != nil {
+		t.Fatalf("scan is failed: %v", err)
+	}
+	act.compare(t, exp)
+	act = nil
+	if err := st1.ScanBytes([]byte("\xff\xfeab"), &act); err != nil {
+		t.Fatalf("scan is failed: %v", err)
+	}
+	act.compare(t, exp)
+
+	if got, id := st1.LongestPrefix("\xe3\x81\x82"); got != "\xe3\x81" || id != 3 {
+		t.Errorf("unexpected LongestPrefix: %q %d", got, id)
+	}
+	if got := collectKeyIDs(st1.PrefixSearch("\xfe")); len(got) != 1 || got[0] != (keyID{"\xfeab", 2}) {
+		t.Errorf("unexpected PrefixSearch: %+v", got)
+	}
+}
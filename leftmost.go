@@ -0,0 +1,170 @@
+package trietree
+
+import "iter"
+
+// MatchKind specifies which matches are reported by ScanWith and PredictWith.
+type MatchKind int
+
+const (
+	// Standard reports all matches, including overlapping ones.
+	Standard MatchKind = iota
+
+	// LeftmostLongest reports non-overlapping matches.  When some keys match
+	// at the same start position, the longest one is reported.
+	LeftmostLongest
+
+	// LeftmostFirst reports non-overlapping matches.  When some keys match
+	// at the same start position, the one which has the smallest edge ID,
+	// that is the earliest inserted one, is reported.
+	LeftmostFirst
+)
+
+// MatchOptions specifies which matches are reported by methods like ScanWith
+// and PredictWith.  Zero value reports all matches, same as Scan and Predict.
+type MatchOptions struct {
+	// Kind specifies a kind of matches.
+	Kind MatchKind
+
+	// WordRune reports whether a rune is a part of words.  When it is not
+	// nil, only matches which are not parts of longer words are reported,
+	// that is, a word rune at an end of a match is not next to another word
+	// rune.  IsWordRune is a typical one.
+	WordRune func(rune) bool
+}
+
+// leftmostFinder finds non-overlapping matches from left to right, with the
+// automaton of the tree.  It walks the automaton only once for the query, and
+// keeps the best match for each start position, until no better matches can
+// be found for the position.
+type leftmostFinder[T comparable, S byteSeq] struct {
+	tree   predictableTree[T]
+	kind   MatchKind
+	isWord func(rune) bool
+	query  S
+
+	index int // index is the index of the next label in query.
+	node  T   // node is the current state of the automaton.
+	count int // count is the number of consumed labels.
+
+	// pos is the number of labels before the next match, which can not
+	// overlap with the previous match.
+	pos int
+	// scan is the number of labels before a candidate of the next match.
+	scan int
+
+	// starts and found hold indexes of labels and the best matches which
+	// start at those labels, for labels from base.
+	base   int
+	starts []int
+	found  []leftmostMatch[T]
+}
+
+// leftmostMatch is a match which is found by leftmostFinder.  id is zero
+// when no matches.
+type leftmostMatch[T comparable] struct {
+	end   int
+	count int // count is the number of labels before end.
+	id    int
+	node  T
+}
+
+func newLeftmostFinder[T comparable, S byteSeq](tree predictableTree[T], query S, opts MatchOptions) *leftmostFinder[T, S] {
+	return &leftmostFinder[T, S]{
+		tree:   tree,
+		kind:   opts.Kind,
+		isWord: opts.WordRune,
+		query:  query,
+		node:   tree.root(),
+	}
+}
+
+// next finds a next match, and returns it with its node.
+func (lf *leftmostFinder[T, S]) next() (p Prediction, node T, ok bool) {
+	for {
+		// the earliest start of partial matches.  No matches start before
+		// it will be found.
+		earliest := lf.count - lf.tree.nodeDepth(lf.node)
+		if lf.index >= len(lf.query) {
+			earliest = lf.count
+		}
+		lf.scan = max(lf.scan, lf.pos)
+		for lf.scan < earliest && lf.found[lf.scan-lf.base].id == 0 {
+			lf.scan++
+		}
+		if lf.scan < earliest {
+			p, node = lf.emit()
+			return p, node, true
+		}
+		if lf.index >= len(lf.query) {
+			return Prediction{}, node, false
+		}
+		lf.step()
+	}
+}
+
+// step consumes a label, and records matches which end at it.
+func (lf *leftmostFinder[T, S]) step() {
+	var zero T
+	tree := lf.tree
+	byteLabels := tree.byteLabels()
+	c, sz := decodeLabel(lf.query[lf.index:], byteLabels)
+	lf.node = tree.nextNode(lf.node, tree.normalize(c))
+	// drop labels which are not used anymore.
+	if k := lf.scan - lf.base; k > 0 && k >= len(lf.starts)/2 {
+		lf.starts = lf.starts[:copy(lf.starts, lf.starts[k:])]
+		lf.found = lf.found[:copy(lf.found, lf.found[k:])]
+		lf.base = lf.scan
+	}
+	lf.starts = append(lf.starts, lf.index)
+	lf.found = append(lf.found, leftmostMatch[T]{})
+	lf.index += sz
+	lf.count++
+	for n := matchedNode(tree, lf.node); n != zero; n = tree.nodeOutput(n) {
+		lv := tree.nodeLevel(n)
+		if lv <= 0 {
+			break
+		}
+		x := lf.count - lv - lf.base
+		start := lf.starts[x]
+		if lf.isWord != nil && !isBoundary(lf.query, start, lf.index, byteLabels, lf.isWord) {
+			continue
+		}
+		id := tree.nodeId(n)
+		if f := &lf.found[x]; f.id == 0 || lf.kind == LeftmostLongest || id < f.id {
+			*f = leftmostMatch[T]{end: lf.index, count: lf.count, id: id, node: n}
+		}
+	}
+}
+
+// emit returns the match at scan, and prepares for the next match.
+func (lf *leftmostFinder[T, S]) emit() (Prediction, T) {
+	f := lf.found[lf.scan-lf.base]
+	p := Prediction{Start: lf.starts[lf.scan-lf.base], End: f.end, ID: f.id}
+	lf.pos = f.count
+	// forget partial matches which overlap with the match.
+	for lf.tree.nodeDepth(lf.node) > lf.count-lf.pos {
+		lf.node = lf.tree.nodeFail(lf.node)
+	}
+	return p, f.node
+}
+
+func (lf *leftmostFinder[T, S]) all() iter.Seq[Prediction] {
+	return func(yield func(Prediction) bool) {
+		for {
+			p, _, ok := lf.next()
+			if !ok || !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+func (lf *leftmostFinder[T, S]) iter() func() *Prediction {
+	return func() *Prediction {
+		p, _, ok := lf.next()
+		if !ok {
+			return nil
+		}
+		return &p
+	}
+}
@@ -0,0 +1,182 @@
+package trietree_test
+
+import (
+	"bytes"
+	"context"
+	"iter"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/koron-go/trietree"
+)
+
+// optionsTree is a tree which predicts and scans with MatchOptions.
+type optionsTree interface {
+	PredictWith(string, trietree.MatchOptions) iter.Seq[trietree.Prediction]
+	PredictIterWith(string, trietree.MatchOptions) trietree.PredictionIter
+	ScanWith(context.Context, string, trietree.MatchOptions, trietree.ScanReporter) error
+}
+
+// withOptions binds MatchOptions to a tree, to satisfy predictor and
+// predictIterator.
+type withOptions struct {
+	tree optionsTree
+	opts trietree.MatchOptions
+}
+
+func (w withOptions) Predict(q string) iter.Seq[trietree.Prediction] {
+	return w.tree.PredictWith(q, w.opts)
+}
+
+func (w withOptions) PredictIter(q string) trietree.PredictionIter {
+	return w.tree.PredictIterWith(q, w.opts)
+}
+
+func testScanWith(t *testing.T, w withOptions, s string, exp reports) {
+	t.Helper()
+	var act reports
+	err := w.tree.ScanWith(context.Background(), s, w.opts, &act)
+	if err != nil {
+		t.Fatalf("scan is failed: %v", err)
+	}
+	act.compare(t, exp)
+}
+
+func TestMatchKind(t *testing.T) {
+	for _, c := range []struct {
+		kind trietree.MatchKind
+		want []prediction
+		scan reports
+	}{
+		{trietree.LeftmostLongest, []prediction{
+			{Start: 0, End: 4, ID: 2, Key: "abcd"},
+			{Start: 5, End: 6, ID: 4, Key: "c"},
+		}, reports{
+			{0, 'a', nil},
+			{1, 'b', nil},
+			{2, 'c', nil},
+			{3, 'd', []node{{2, 4}}},
+			{4, 'x', nil},
+			{5, 'c', []node{{4, 1}}},
+		}},
+		{trietree.LeftmostFirst, []prediction{
+			{Start: 0, End: 2, ID: 1, Key: "ab"},
+			{Start: 2, End: 3, ID: 4, Key: "c"},
+			{Start: 5, End: 6, ID: 4, Key: "c"},
+		}, reports{
+			{0, 'a', nil},
+			{1, 'b', []node{{1, 2}}},
+			{2, 'c', []node{{4, 1}}},
+			{3, 'd', nil},
+			{4, 'x', nil},
+			{5, 'c', []node{{4, 1}}},
+		}},
+	} {
+		const q = "abcdxc"
+		dt := testDTreePut(t, &trietree.DTree{}, "ab", "abcd", "bcd", "c", "cdx")
+		opts := trietree.MatchOptions{Kind: c.kind}
+		for _, tree := range []optionsTree{dt, trietree.Freeze(dt), trietree.FreezeDA(dt)} {
+			w := withOptions{tree, opts}
+			testPredict(t, w, q, c.want)
+			testPredictIter(t, w, q, c.want)
+			testScanWith(t, w, q, c.scan)
+		}
+	}
+}
+
+// bruteForceLeftmost enumerates non-overlapping matches of keys in q, by
+// checking all keys at each position.
+func bruteForceLeftmost(keys []string, q string, kind trietree.MatchKind) []prediction {
+	got := []prediction{}
+	for i := 0; i < len(q); {
+		best := -1
+		for j, k := range keys {
+			if !strings.HasPrefix(q[i:], k) {
+				continue
+			}
+			if best < 0 || kind == trietree.LeftmostLongest && len(k) > len(keys[best]) {
+				best = j
+			}
+		}
+		if best < 0 {
+			_, sz := utf8.DecodeRuneInString(q[i:])
+			i += sz
+			continue
+		}
+		k := keys[best]
+		got = append(got, prediction{Start: i, End: i + len(k), ID: best + 1, Key: k})
+		i += len(k)
+	}
+	return got
+}
+
+func TestMatchKind_random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randString := func(n int) string {
+		rs := make([]rune, 1+rnd.Intn(n))
+		for i := range rs {
+			rs[i] = []rune("abあ")[rnd.Intn(3)]
+		}
+		return string(rs)
+	}
+	for _, kind := range []trietree.MatchKind{trietree.LeftmostLongest, trietree.LeftmostFirst} {
+		for range 50 {
+			var keys []string
+			dt := &trietree.DTree{}
+			for range 8 {
+				k := randString(5)
+				if slices.Contains(keys, k) {
+					continue
+				}
+				keys = append(keys, k)
+				dt.Put(k)
+			}
+			st := trietree.Freeze(dt)
+			da := trietree.FreezeDA(dt)
+			// depths of DATree are rebuilt by ReadDA.
+			b := &bytes.Buffer{}
+			if err := da.Write(b); err != nil {
+				t.Fatalf("write failed: %s", err)
+			}
+			da2, err := trietree.ReadDA(b)
+			if err != nil {
+				t.Fatalf("read failed: %s", err)
+			}
+			opts := trietree.MatchOptions{Kind: kind}
+			for range 10 {
+				q := randString(30)
+				want := bruteForceLeftmost(keys, q, kind)
+				testPredict(t, withOptions{dt, opts}, q, want)
+				testPredict(t, withOptions{st, opts}, q, want)
+				testPredict(t, withOptions{da, opts}, q, want)
+				testPredict(t, withOptions{da2, opts}, q, want)
+				testPredict(t, withOptions{&trietree.STree{Nodes: st.Nodes, Levels: st.Levels}, opts}, q, want)
+			}
+		}
+	}
+}
+
+// longKeyQuery has many partial matches of the long key of newLongKeyTree.
+var longKeyQuery = strings.Repeat("a", 200000)
+
+// newLongKeyTree returns a tree which has a long key and a short key.
+// Walking the tree from each position of longKeyQuery takes quadratic time.
+func newLongKeyTree() *trietree.DTree {
+	dt := &trietree.DTree{}
+	dt.Put(strings.Repeat("a", 2000) + "b")
+	dt.Put("a")
+	return dt
+}
+
+func BenchmarkPredict_longKey(b *testing.B) {
+	st := trietree.Freeze(newLongKeyTree())
+	opts := trietree.MatchOptions{Kind: trietree.LeftmostLongest}
+	b.ResetTimer()
+	for range b.N {
+		for range st.PredictWith(longKeyQuery, opts) {
+		}
+	}
+}
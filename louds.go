@@ -0,0 +1,257 @@
+package trietree
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+)
+
+// LTree is static tree in LOUDS (level-order unary degree sequence) form.
+// Shape of the tree is encoded into about two bits per node, so it takes a
+// fraction of memory of STree.  Instead, moving from a node to its children
+// takes more time.  LTree is built from DTree by FreezeLOUDS.  It supports
+// Get, LongestPrefix and prefix enumeration, but not Scan and Predict.
+//
+// Nodes are numbered in breadth first order from the root, which is 0.
+type LTree struct {
+	// Normalizer is applied to queries when not nil.  It is not serialized,
+	// so set same one with the original DTree after ReadLOUDS.
+	Normalizer Normalizer
+
+	// ByteLabels makes each byte of queries a label, instead of each rune
+	// decoded from UTF-8.  It is copied from DTree by FreezeLOUDS, and
+	// serialized.
+	ByteLabels bool
+
+	// louds has a one for each child and a zero for the end of children, for
+	// each node in order.  It starts with "10" for a virtual parent of the
+	// root.
+	louds bitVector
+	// labels is a label of each node.
+	labels []rune
+	// terminals has a one for each node which has an edge.
+	terminals bitVector
+	// ids is edge IDs of nodes which have a one in terminals.
+	ids []uint32
+}
+
+// FreezeLOUDS converts dynamic tree to static tree in LOUDS form.
+func FreezeLOUDS(src *DTree) *LTree {
+	lt := &LTree{
+		ByteLabels: src.ByteLabels,
+		Normalizer: src.Normalizer,
+	}
+	lt.louds.push(true)
+	lt.louds.push(false)
+	queue := []*DNode{&src.Root}
+	for len(queue) > 0 {
+		dn := queue[0]
+		queue = queue[1:]
+		lt.labels = append(lt.labels, dn.Label)
+		lt.terminals.push(dn.EdgeID > 0)
+		if dn.EdgeID > 0 {
+			lt.ids = append(lt.ids, uint32(dn.EdgeID))
+		}
+		dn.Child.eachSiblings(func(c *DNode) {
+			lt.louds.push(true)
+			queue = append(queue, c)
+		})
+		lt.louds.push(false)
+	}
+	lt.louds.build()
+	lt.terminals.build()
+	return lt
+}
+
+// childRange returns a range of node numbers of children of node x.
+func (lt *LTree) childRange(x int) (start, end int) {
+	a := lt.louds.select0(x) + 1
+	b := lt.louds.select0(x + 1)
+	start = lt.louds.rank1(a)
+	return start, start + b - a
+}
+
+// methods LTree satisfies searchableTree[int]
+func (lt *LTree) root() int        { return 0 }
+func (lt *LTree) byteLabels() bool { return lt.ByteLabels }
+
+func (lt *LTree) normalize(r rune) rune {
+	if lt.Normalizer == nil {
+		return r
+	}
+	return lt.Normalizer(r)
+}
+
+func (lt *LTree) nodeId(x int) int {
+	if !lt.terminals.get(x) {
+		return 0
+	}
+	return int(lt.ids[lt.terminals.rank1(x)])
+}
+
+func (lt *LTree) child(x int, r rune) (int, bool) {
+	a, b := lt.childRange(x)
+	i := a + sort.Search(b-a, func(i int) bool {
+		return lt.labels[a+i] >= r
+	})
+	if i < b && lt.labels[i] == r {
+		return i, true
+	}
+	return 0, false
+}
+
+func (lt *LTree) children(x int) iter.Seq2[rune, int] {
+	return lt.childrenFrom(x, 0)
+}
+
+func (lt *LTree) childrenFrom(x int, from rune) iter.Seq2[rune, int] {
+	return func(yield func(rune, int) bool) {
+		a, b := lt.childRange(x)
+		i := a + sort.Search(b-a, func(i int) bool {
+			return lt.labels[a+i] >= from
+		})
+		for ; i < b; i++ {
+			if !yield(lt.labels[i], i) {
+				return
+			}
+		}
+	}
+}
+
+// Get finds an edge for key k, and returns its ID.
+// ok will be false when k is not a key of the tree.
+func (lt *LTree) Get(k string) (edgeID int, ok bool) {
+	x := 0
+	for i := 0; i < len(k); {
+		r, sz := decodeLabel(k[i:], lt.ByteLabels)
+		x, ok = lt.child(x, lt.normalize(r))
+		if !ok {
+			return 0, false
+		}
+		i += sz
+	}
+	edgeID = lt.nodeId(x)
+	return edgeID, edgeID > 0
+}
+
+// LongestPrefix finds a longest prefix node/edge matches given s string.
+func (lt *LTree) LongestPrefix(s string) (prefix string, edgeID int) {
+	end := 0
+	curr := 0
+	for i := 0; i < len(s); {
+		r, sz := decodeLabel(s[i:], lt.ByteLabels)
+		next, ok := lt.child(curr, lt.normalize(r))
+		if !ok {
+			break
+		}
+		i += sz
+		if id := lt.nodeId(next); id > 0 {
+			edgeID = id
+			end = i
+		}
+		curr = next
+	}
+	return s[:end], edgeID
+}
+
+// CommonPrefixes returns an iterator which enumerates all keys which are
+// prefixes of s, from shorter to longer. Start of each Prediction is always
+// zero.
+func (lt *LTree) CommonPrefixes(s string) iter.Seq[Prediction] {
+	return commonPrefixes[int](lt, s)
+}
+
+// PrefixSearch returns an iterator which enumerates all keys which start with
+// prefix, and their edge IDs.  Keys are enumerated in lexicographic order.
+func (lt *LTree) PrefixSearch(prefix string) iter.Seq2[string, int] {
+	return prefixSearch[int](lt, prefix)
+}
+
+// All returns an iterator which enumerates all keys in the tree, and their
+// edge IDs.  Keys are enumerated in lexicographic order.
+func (lt *LTree) All() iter.Seq2[string, int] {
+	return all[int](lt)
+}
+
+// loudsFormatTag is written at the head of serialized LTree as a negated
+// number, to distinguish it from other trees.  The lowest 8 bits are the
+// version.
+const loudsFormatTag = 'L'<<8 | 1
+
+// Write serializes a tree to io.Writer.
+func (lt *LTree) Write(w io.Writer) error {
+	ww := newWriter(w)
+
+	// write header.
+	var flags int
+	if lt.ByteLabels {
+		flags |= flagByteLabels
+	}
+	ww.writeInt(-loudsFormatTag)
+	ww.writeInt(flags)
+
+	lt.louds.write(ww)
+	ww.writeInt(len(lt.labels))
+	for _, r := range lt.labels {
+		ww.writeRune(r)
+	}
+	lt.terminals.write(ww)
+	ww.writeInt(len(lt.ids))
+	for _, id := range lt.ids {
+		ww.writeInt(int(id))
+	}
+	if ww.err != nil {
+		return ww.err
+	}
+	return ww.w.Flush()
+}
+
+// ReadLOUDS reads static tree in LOUDS form from io.Reader.
+func ReadLOUDS(r io.Reader) (*LTree, error) {
+	rr := newReader(r)
+
+	// read header.
+	if v := rr.readInt(); rr.err == nil && v != -loudsFormatTag {
+		return nil, errors.New("not a LOUDS tree or unsupported version")
+	}
+	flags := rr.readInt()
+	if rr.err != nil {
+		return nil, rr.err
+	}
+	if flags&^flagByteLabels != 0 {
+		return nil, fmt.Errorf("unknown format flags: %#x", flags&^flagByteLabels)
+	}
+
+	lt := &LTree{ByteLabels: flags&flagByteLabels != 0}
+	if err := lt.louds.read(rr); err != nil {
+		return nil, err
+	}
+	n, err := rr.readSize()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errors.New("no root node")
+	}
+	lt.labels = make([]rune, n)
+	for i := range lt.labels {
+		lt.labels[i] = rr.readRune()
+	}
+	if err := lt.terminals.read(rr); err != nil {
+		return nil, err
+	}
+	n, err = rr.readSize()
+	if err != nil {
+		return nil, err
+	}
+	lt.ids = make([]uint32, n)
+	for i := range lt.ids {
+		lt.ids[i] = uint32(rr.readInt())
+	}
+	if rr.err != nil {
+		return nil, rr.err
+	}
+	return lt, nil
+}
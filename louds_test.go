@@ -0,0 +1,96 @@
+package trietree_test
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+func testLTree(t *testing.T, lt *trietree.LTree, st *trietree.STree, keys, queries []string) {
+	t.Helper()
+	for i, k := range keys {
+		if id, ok := lt.Get(k); !ok || id != i+1 {
+			t.Errorf("unexpected Get for %q: %d %t", k, id, ok)
+		}
+	}
+	for _, q := range queries {
+		wantID, wantOK := st.Get(q)
+		if id, ok := lt.Get(q); id != wantID || ok != wantOK {
+			t.Errorf("unexpected Get for %q: want=(%d, %t) got=(%d, %t)", q, wantID, wantOK, id, ok)
+		}
+		wantPrefix, wantID := st.LongestPrefix(q)
+		if prefix, id := lt.LongestPrefix(q); prefix != wantPrefix || id != wantID {
+			t.Errorf("unexpected LongestPrefix for %q: want=(%q, %d) got=(%q, %d)", q, wantPrefix, wantID, prefix, id)
+		}
+		if d := cmp.Diff(slices.Collect(st.CommonPrefixes(q)), slices.Collect(lt.CommonPrefixes(q))); d != "" {
+			t.Errorf("unexpected CommonPrefixes for %q: -want +got\n%s", q, d)
+		}
+		if d := cmp.Diff(collectKeyIDs(st.PrefixSearch(q)), collectKeyIDs(lt.PrefixSearch(q))); d != "" {
+			t.Errorf("unexpected PrefixSearch for %q: -want +got\n%s", q, d)
+		}
+	}
+	if d := cmp.Diff(collectKeyIDs(st.All()), collectKeyIDs(lt.All())); d != "" {
+		t.Errorf("unexpected All: -want +got\n%s", d)
+	}
+}
+
+func TestLTree(t *testing.T) {
+	keys := []string{"ab", "bc", "bab", "d", "abcde", "あい", "い", "あいう", "xyz"}
+	dt := testDTreePut(t, &trietree.DTree{}, keys...)
+	lt0 := trietree.FreezeLOUDS(dt)
+	b := &bytes.Buffer{}
+	if err := lt0.Write(b); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	lt1, err := trietree.ReadLOUDS(b)
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	queries := []string{"", "a", "abc", "abcdef", "あいうえ", "z", "ba", "い"}
+	st := trietree.Freeze(dt)
+	testLTree(t, lt0, st, keys, queries)
+	testLTree(t, lt1, st, keys, queries)
+
+	empty := &trietree.DTree{}
+	testLTree(t, trietree.FreezeLOUDS(empty), trietree.Freeze(empty), nil, queries)
+}
+
+func TestLTree_random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randString := func(n int) string {
+		rs := make([]rune, 1+rnd.Intn(n))
+		for i := range rs {
+			rs[i] = []rune("abcdefあいう")[rnd.Intn(9)]
+		}
+		return string(rs)
+	}
+	var keys []string
+	dt := &trietree.DTree{}
+	for range 2000 {
+		k := randString(8)
+		if slices.Contains(keys, k) {
+			continue
+		}
+		keys = append(keys, k)
+		dt.Put(k)
+	}
+	queries := make([]string, 100)
+	for i := range queries {
+		queries[i] = randString(10)
+	}
+	testLTree(t, trietree.FreezeLOUDS(dt), trietree.Freeze(dt), keys, queries)
+}
+
+func TestReadLOUDS_invalid(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := trietree.FreezeDA(&trietree.DTree{}).Write(b); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	if _, err := trietree.ReadLOUDS(b); err == nil {
+		t.Fatal("unexpected success to read DATree as LTree")
+	}
+}
@@ -0,0 +1,158 @@
+package trietree
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// Matcher scans a stream of bytes chunk by chunk, and reports keys which
+// match in the stream.  It keeps the state of the automaton between chunks,
+// so keys which span chunks are reported too.  Start and End of reported
+// Prediction are absolute offsets in the whole stream.  Matcher always
+// reports all matches like Standard of MatchKind, and doesn't take
+// MatchOptions, because those need to look ahead.
+// Modifying a tree invalidates its matchers.
+type Matcher interface {
+	// Write scans p as a continuation of bytes written before.  A rune
+	// which is split at the end of p is held until following bytes are
+	// written.  This never fails.
+	Write(p []byte) (int, error)
+
+	// ReadFrom scans all bytes from r until EOF, then calls Flush.
+	ReadFrom(r io.Reader) (int64, error)
+
+	// Flush scans bytes of an incomplete rune which is held at the end of
+	// the stream, as invalid runes.
+	Flush()
+
+	// Offset returns the number of bytes which were scanned.
+	Offset() int
+
+	// Reset resets the matcher to scan a new stream.
+	Reset()
+}
+
+// Matcher creates a new Matcher which calls fn for each key which matches in
+// a stream.
+func (dt *DTree) Matcher(fn func(Prediction)) Matcher {
+	dt.ensureFailure()
+	maxLevel := 0
+	for _, n := range dt.edges {
+		if n != nil {
+			maxLevel = max(maxLevel, n.Level)
+		}
+	}
+	return newMatcher[*DNode](dt, maxLevel, fn)
+}
+
+// Matcher creates a new Matcher which calls fn for each key which matches in
+// a stream.
+func (st *STree) Matcher(fn func(Prediction)) Matcher {
+	maxLevel := 0
+	for _, lv := range st.Levels {
+		maxLevel = max(maxLevel, lv)
+	}
+	return newMatcher[int](st, maxLevel, fn)
+}
+
+type matcher[T comparable] struct {
+	tree   predictableTree[T]
+	report func(Prediction)
+
+	node   T
+	offset int
+
+	// pending holds bytes of an incomplete rune, backed by pendingBuf.
+	pending    []byte
+	pendingBuf [utf8.UTFMax]byte
+
+	// starts is a ring buffer which holds start offsets of recent labels.
+	starts []int
+	count  int
+}
+
+func newMatcher[T comparable](tree predictableTree[T], maxLevel int, fn func(Prediction)) *matcher[T] {
+	m := &matcher[T]{
+		tree:   tree,
+		report: fn,
+		starts: make([]int, max(maxLevel, 1)),
+	}
+	m.Reset()
+	return m
+}
+
+func (m *matcher[T]) Write(p []byte) (int, error) {
+	n := len(p)
+	// complete the pending rune with head of p.
+	for len(m.pending) > 0 && len(p) > 0 {
+		m.pending = append(m.pending, p[0])
+		p = p[1:]
+		for len(m.pending) > 0 && utf8.FullRune(m.pending) {
+			r, sz := utf8.DecodeRune(m.pending)
+			m.step(r, sz)
+			m.pending = m.pending[:copy(m.pending, m.pending[sz:])]
+		}
+	}
+	byteLabels := m.tree.byteLabels()
+	for i := 0; i < len(p); {
+		if !byteLabels && !utf8.FullRune(p[i:]) {
+			m.pending = append(m.pending, p[i:]...)
+			break
+		}
+		r, sz := decodeLabel(p[i:], byteLabels)
+		m.step(r, sz)
+		i += sz
+	}
+	return n, nil
+}
+
+func (m *matcher[T]) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	buf := make([]byte, 32*1024)
+	for {
+		k, err := r.Read(buf)
+		if k > 0 {
+			m.Write(buf[:k])
+			n += int64(k)
+		}
+		if err == io.EOF {
+			m.Flush()
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+func (m *matcher[T]) Flush() {
+	for len(m.pending) > 0 {
+		r, sz := utf8.DecodeRune(m.pending)
+		m.step(r, sz)
+		m.pending = m.pending[:copy(m.pending, m.pending[sz:])]
+	}
+}
+
+func (m *matcher[T]) Offset() int {
+	return m.offset
+}
+
+func (m *matcher[T]) Reset() {
+	m.node = m.tree.root()
+	m.offset = 0
+	m.pending = m.pendingBuf[:0]
+	m.count = 0
+}
+
+// step consumes a label of sz bytes, and reports matched keys.
+func (m *matcher[T]) step(c rune, sz int) {
+	var zero T
+	m.starts[m.count%len(m.starts)] = m.offset
+	m.count++
+	m.offset += sz
+	m.node = m.tree.nextNode(m.node, m.tree.normalize(c))
+	for n := matchedNode(m.tree, m.node); n != zero; n = m.tree.nodeOutput(n) {
+		x := (m.count - m.tree.nodeLevel(n)) % len(m.starts)
+		m.report(Prediction{Start: m.starts[x], End: m.offset, ID: m.tree.nodeId(n)})
+	}
+}
@@ -0,0 +1,64 @@
+package trietree_test
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+type matcherTree interface {
+	Predict(string) iter.Seq[trietree.Prediction]
+	Matcher(func(trietree.Prediction)) trietree.Matcher
+}
+
+func testMatcher(t *testing.T, tree matcherTree, s string) {
+	t.Helper()
+	want := slices.Collect(tree.Predict(s))
+	var got []trietree.Prediction
+	m := tree.Matcher(func(p trietree.Prediction) {
+		got = append(got, p)
+	})
+	// write s in chunks of every size.
+	for size := 1; size <= len(s); size++ {
+		got = nil
+		m.Reset()
+		for i := 0; i < len(s); i += size {
+			m.Write([]byte(s[i:min(i+size, len(s))]))
+		}
+		m.Flush()
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("unexpected matches with chunk size %d: -want +got\n%s", size, d)
+		}
+		if m.Offset() != len(s) {
+			t.Errorf("unexpected offset with chunk size %d: %d", size, m.Offset())
+		}
+	}
+	// read s one byte by one byte.
+	got = nil
+	m.Reset()
+	n, err := m.ReadFrom(iotest.OneByteReader(strings.NewReader(s)))
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %s", err)
+	}
+	if n != int64(len(s)) {
+		t.Errorf("unexpected read size: %d", n)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("unexpected matches with ReadFrom: -want +got\n%s", d)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	dt := testDTreePut(t, &trietree.DTree{}, "ab", "bc", "bab", "d", "abcde", "あい", "いう", "\xe3")
+	for _, tree := range []matcherTree{dt, trietree.Freeze(dt)} {
+		testMatcher(t, tree, "abcdebabd")
+		testMatcher(t, tree, "xあいうえabcあ")
+		// an incomplete rune at the end is flushed as an invalid rune.
+		testMatcher(t, tree, "aあい\xe3\x81")
+	}
+}
@@ -0,0 +1,78 @@
+package trietree_test
+
+import (
+	"bytes"
+	"testing"
+	"unicode"
+
+	"github.com/koron-go/trietree"
+)
+
+func TestNormalizer(t *testing.T) {
+	dt := &trietree.DTree{Normalizer: unicode.ToLower}
+	testDTreePut(t, dt, "Kelvin", "vin", "ab")
+	st0 := trietree.Freeze(dt)
+	b := &bytes.Buffer{}
+	if err := st0.Write(b); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	st1, err := trietree.Read(b)
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	st1.Normalizer = unicode.ToLower
+
+	// U+212A is KELVIN SIGN, which is 3 bytes in UTF-8, and lowered to 'k'.
+	const q = "x\u212AELVIN AB"
+	for _, tree := range []interface {
+		predictor
+		commonPrefixer
+		Get(string) (int, bool)
+		LongestPrefix(string) (string, int)
+	}{
+		dtreeGetter{dt}, st0, st1,
+	} {
+		testPredict(t, tree, q, []prediction{
+			{Start: 1, End: 9, ID: 1, Key: "\u212AELVIN"},
+			{Start: 6, End: 9, ID: 2, Key: "VIN"},
+			{Start: 10, End: 12, ID: 3, Key: "AB"},
+		})
+		if got, id := tree.LongestPrefix(q[1:]); got != "\u212AELVIN" || id != 1 {
+			t.Errorf("unexpected LongestPrefix for %T: %q %d", tree, got, id)
+		}
+		if id, ok := tree.Get("KELVIN"); !ok || id != 1 {
+			t.Errorf("unexpected Get for %T: %d %t", tree, id, ok)
+		}
+		var got []prediction
+		for p := range tree.CommonPrefixes("\u212Aelvins") {
+			got = append(got, prediction{Start: p.Start, End: p.End, ID: p.ID})
+		}
+		if len(got) != 1 || got[0] != (prediction{Start: 0, End: 8, ID: 1}) {
+			t.Errorf("unexpected CommonPrefixes for %T: %+v", tree, got)
+		}
+	}
+
+	testDTreeScan(t, dt, "\u212AAB", reports{
+		{0, '\u212A', nil},
+		{3, 'A', nil},
+		{4, 'B', []node{{3, 2}}},
+	})
+	testSTreeScan(t, st1, "\u212AAB", reports{
+		{0, '\u212A', nil},
+		{3, 'A', nil},
+		{4, 'B', []node{{3, 2}}},
+	})
+}
+
+// dtreeGetter adapts DTree.Get to the signature of STree.Get.
+type dtreeGetter struct {
+	*trietree.DTree
+}
+
+func (dg dtreeGetter) Get(k string) (int, bool) {
+	n := dg.DTree.Get(k)
+	if n == nil || n.EdgeID == 0 {
+		return 0, false
+	}
+	return n.EdgeID, true
+}
@@ -0,0 +1,110 @@
+package trietree_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+// bruteForcePredict enumerates matches of keys in q, same order as Predict.
+func bruteForcePredict(keys []string, q string) []prediction {
+	got := []prediction{}
+	for end := 1; end <= len(q); end++ {
+		var matches []prediction
+		for i, k := range keys {
+			if strings.HasSuffix(q[:end], k) {
+				matches = append(matches, prediction{Start: end - len(k), End: end, ID: i + 1, Key: k})
+			}
+		}
+		// longer keys are reported first.
+		slices.SortFunc(matches, func(a, b prediction) int { return a.Start - b.Start })
+		got = append(got, matches...)
+	}
+	return got
+}
+
+func TestOutputLinks(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randString := func(n int) string {
+		b := make([]byte, 1+rnd.Intn(n))
+		for i := range b {
+			b[i] = "ab"[rnd.Intn(2)]
+		}
+		return string(b)
+	}
+	for range 20 {
+		var keys []string
+		dt := &trietree.DTree{}
+		for range 10 {
+			k := randString(6)
+			if slices.Contains(keys, k) {
+				continue
+			}
+			keys = append(keys, k)
+			dt.Put(k)
+		}
+		st := trietree.Freeze(dt)
+		for range 10 {
+			q := randString(20)
+			want := bruteForcePredict(keys, q)
+			testPredict(t, dt, q, want)
+			testPredict(t, st, q, want)
+			testPredictIter(t, dt, q, want)
+			testPredictIter(t, st, q, want)
+		}
+	}
+}
+
+func TestSTree_assembledOutputs(t *testing.T) {
+	st0 := trietree.Freeze(testDTreePut(t, &trietree.DTree{}, "bab", "ab"))
+	st := &trietree.STree{Nodes: st0.Nodes, Levels: st0.Levels}
+	want := []prediction{
+		{Start: 0, End: 3, ID: 1, Key: "bab"},
+		{Start: 1, End: 3, ID: 2, Key: "ab"},
+	}
+	testPredict(t, st, "bab", want)
+	testPredictIter(t, st, "bab", want)
+	counts := make([]uint32, 2)
+	st.Count("bab", counts)
+	if d := cmp.Diff([]uint32{1, 1}, counts); d != "" {
+		t.Errorf("unexpected counts: -want +got\n%s", d)
+	}
+}
+
+func TestSTree_readWithoutOutputs(t *testing.T) {
+	dt := testDTreePut(t, &trietree.DTree{}, "ab", "bc", "bab", "d", "abcde", "b")
+	st0 := trietree.Freeze(dt)
+
+	// compose the first version of serialized format, which has no header
+	// and no Output.
+	var b []byte
+	b = binary.AppendVarint(b, int64(len(st0.Nodes)))
+	for _, n := range st0.Nodes {
+		for _, v := range []int{int(n.Label), n.Start, n.End, n.Fail, n.EdgeID} {
+			b = binary.AppendVarint(b, int64(v))
+		}
+	}
+	b = binary.AppendVarint(b, int64(len(st0.Levels)))
+	for _, lv := range st0.Levels {
+		b = binary.AppendVarint(b, int64(lv))
+	}
+
+	st1, err := trietree.Read(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	if d := cmp.Diff(st0.Nodes, st1.Nodes); d != "" {
+		t.Errorf("unexpected nodes: -want +got\n%s", d)
+	}
+	testSTreeScan(t, st1, "bab", reports{
+		{0, 'b', []node{{6, 1}}},
+		{1, 'a', nil},
+		{2, 'b', []node{{3, 3}, {1, 2}, {6, 1}}},
+	})
+}
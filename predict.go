@@ -2,7 +2,6 @@ package trietree
 
 import (
 	"iter"
-	"unicode/utf8"
 )
 
 // Prediction is identifier of a key.
@@ -18,44 +17,109 @@ type PredictionIter func() *Prediction
 // PredictIter returns an iterator function PredictionIter, which enumerates
 // Prediction: key suggestions that match the query in the tree.
 func (dt *DTree) PredictIter(query string) PredictionIter {
-	return predictIter[*DNode](dt, query)
+	return dt.PredictIterWith(query, MatchOptions{})
 }
 
 // PredictIter returns an iterator function PredictionIter, which enumerates
 // Prediction: key suggestions that match the query in the tree.
 func (st *STree) PredictIter(query string) PredictionIter {
-	return predictIter[int](st, query)
+	return st.PredictIterWith(query, MatchOptions{})
+}
+
+// PredictIterWith is same as PredictIter, but reports only matches which are
+// specified by opts.
+func (dt *DTree) PredictIterWith(query string, opts MatchOptions) PredictionIter {
+	dt.ensureFailure()
+	return predictIter[*DNode](dt, query, opts)
+}
+
+// PredictIterWith is same as PredictIter, but reports only matches which are
+// specified by opts.
+func (st *STree) PredictIterWith(query string, opts MatchOptions) PredictionIter {
+	return predictIter[int](st.indexed(), query, opts)
 }
 
 type predictableTree[T comparable] interface {
 	root() T
+	byteLabels() bool
+	normalize(rune) rune
+	child(T, rune) (T, bool)
 	nextNode(T, rune) T
 	nodeId(T) int
 	nodeLevel(T) int
+	nodeDepth(T) int
 	nodeFail(T) T
+	nodeOutput(T) T
 }
 
 // methods DTree satisfies predictableTree[*DNode]
-func (dt *DTree) root() *DNode             { return &dt.Root }
-func (dt *DTree) nodeId(n *DNode) int      { return n.EdgeID }
-func (dt *DTree) nodeLevel(n *DNode) int   { return n.Level }
-func (dt *DTree) nodeFail(n *DNode) *DNode { return n.Failure }
+func (dt *DTree) root() *DNode               { return &dt.Root }
+func (dt *DTree) byteLabels() bool           { return dt.ByteLabels }
+func (dt *DTree) nodeId(n *DNode) int        { return n.EdgeID }
+func (dt *DTree) nodeLevel(n *DNode) int     { return n.Level }
+func (dt *DTree) nodeDepth(n *DNode) int     { return n.Level }
+func (dt *DTree) nodeOutput(n *DNode) *DNode { return n.Output }
+
+func (dt *DTree) nodeFail(n *DNode) *DNode {
+	if n.Failure == nil {
+		return &dt.Root
+	}
+	return n.Failure
+}
 
 // methods STree satisfies predictableTree[int]
-func (st *STree) root() int           { return 0 }
-func (st *STree) nodeId(n int) int    { return st.Nodes[n].EdgeID }
-func (st *STree) nodeLevel(n int) int { return st.Levels[st.nodeId(n)-1] }
-func (st *STree) nodeFail(n int) int  { return st.Nodes[n].Fail }
+func (st *STree) root() int        { return 0 }
+func (st *STree) byteLabels() bool { return st.ByteLabels }
+func (st *STree) nodeId(n int) int { return st.Nodes[n].EdgeID }
+
+// matchedNode returns the first node which has an edge, in the chain of output
+// links which starts from n itself.  This returns zero value of T when no
+// such nodes.
+func matchedNode[T comparable](tree predictableTree[T], n T) T {
+	var zero T
+	if n == zero || tree.nodeId(n) > 0 {
+		return n
+	}
+	return tree.nodeOutput(n)
+}
+
+func (st *STree) nodeOutput(n int) int {
+	if st.outputs != nil {
+		return st.outputs[n]
+	}
+	// the tree is assembled by hand, so walk Fail.
+	for f := st.Nodes[n].Fail; f > 0; f = st.Nodes[f].Fail {
+		if st.Nodes[f].EdgeID > 0 {
+			return f
+		}
+	}
+	return 0
+}
+
+func (st *STree) nodeDepth(n int) int {
+	return st.depths[n]
+}
 
-type traverser[T comparable] struct {
+func (st *STree) nodeFail(n int) int {
+	return st.Nodes[n].Fail
+}
+
+func (st *STree) nodeLevel(n int) int {
+	if id := st.nodeId(n); id-1 < len(st.Levels) {
+		return st.Levels[id-1]
+	}
+	return -1
+}
+
+type traverser[T comparable, S byteSeq] struct {
 	tree  predictableTree[T]
-	query string
+	query S
 	pivot T
 	index int
 }
 
-func newTraverser[T comparable](tree predictableTree[T], query string) traverser[T] {
-	return traverser[T]{
+func newTraverser[T comparable, S byteSeq](tree predictableTree[T], query S) traverser[T, S] {
+	return traverser[T, S]{
 		tree:  tree,
 		query: query,
 		pivot: tree.root(),
@@ -63,32 +127,35 @@ func newTraverser[T comparable](tree predictableTree[T], query string) traverser
 	}
 }
 
-// next consumes a rune from query, and determine next node to travese tree.
-// this returns next node, and tail index of last parsed rune in query.
-func (tr *traverser[T]) next() (node T, end int, valid bool) {
+// next consumes a label from query, and determine next node to travese tree.
+// this returns next node, and tail index of last parsed label in query.
+func (tr *traverser[T, S]) next() (node T, end int, valid bool) {
 	var zero T
-	if tr.query == "" {
+	if len(tr.query) == 0 {
 		return zero, 0, false
 	}
-	r, sz := utf8.DecodeRuneInString(tr.query)
+	r, sz := decodeLabel(tr.query, tr.tree.byteLabels())
 	if sz == 0 {
 		return zero, 0, false
 	}
 	tr.query = tr.query[sz:]
 	tr.index += sz
-	tr.pivot = tr.tree.nextNode(tr.pivot, r)
+	tr.pivot = tr.tree.nextNode(tr.pivot, tr.tree.normalize(r))
 	return tr.pivot, tr.index, true
 }
 
-func (tr *traverser[T]) close() {
-	tr.query = ""
+func (tr *traverser[T, S]) close() {
+	tr.query = tr.query[len(tr.query):]
 }
 
-// trailingIndex returns the index of the n'th character from the end of string s.
-func trailingIndex(s string, n int) int {
+// trailingIndex returns the index of the n'th label from the end of s.
+func trailingIndex[S byteSeq](s S, n int, byteLabels bool) int {
+	if byteLabels {
+		return max(len(s)-n, 0)
+	}
 	x := len(s)
 	for n > 0 && x > 0 {
-		_, sz := utf8.DecodeLastRuneInString(s[:x])
+		_, sz := decodeLastLabel(s[:x], false)
 		if sz == 0 {
 			break
 		}
@@ -98,12 +165,17 @@ func trailingIndex(s string, n int) int {
 	return x
 }
 
-func predictIter[T comparable](tree predictableTree[T], query string) func() *Prediction {
+func predictIter[T comparable, S byteSeq](tree predictableTree[T], query S, opts MatchOptions) func() *Prediction {
+	if opts.Kind != Standard {
+		return newLeftmostFinder(tree, query, opts).iter()
+	}
 	var (
-		tr   = newTraverser[T](tree, query)
-		req  = true
-		node T
-		end  int
+		zero   T
+		tr     = newTraverser[T](tree, query)
+		isWord = opts.WordRune
+		req    = true
+		node   T
+		end    int
 	)
 	return func() *Prediction {
 		//log.Printf("predictIter: req=%t end=%d node=%+v", req, end, node)
@@ -118,18 +190,18 @@ func predictIter[T comparable](tree predictableTree[T], query string) func() *Pr
 					tr.close()
 					return nil
 				}
-				req = false
+				node = matchedNode(tree, node)
 			}
-			for !req && p == nil {
+			for p == nil && node != zero {
 				id := tree.nodeId(node)
 				//log.Printf("  id=%d node=%+v", id, node)
-				if id > 0 {
-					st := trailingIndex(query[:end], tree.nodeLevel(node))
+				st := trailingIndex(query[:end], tree.nodeLevel(node), tree.byteLabels())
+				if isWord == nil || isBoundary(query, st, end, tree.byteLabels(), isWord) {
 					p = &Prediction{Start: st, End: end, ID: id}
 				}
-				req = node == tree.root()
-				node = tree.nodeFail(node)
+				node = tree.nodeOutput(node)
 			}
+			req = node == zero
 		}
 		//log.Printf("  return p=%+v node=%+v", p, node)
 		return p
@@ -139,33 +211,62 @@ func predictIter[T comparable](tree predictableTree[T], query string) func() *Pr
 // Predict returns an iterator which enumerates Prediction: key suggestions
 // that match the query in the tree.
 func (dt *DTree) Predict(query string) iter.Seq[Prediction] {
-	return predict[*DNode](dt, query)
+	return dt.PredictWith(query, MatchOptions{})
 }
 
 // Predict returns an iterator which enumerates Prediction: key suggestions
 // that match the query in the tree.
 func (st *STree) Predict(query string) iter.Seq[Prediction] {
-	return predict[int](st, query)
+	return st.PredictWith(query, MatchOptions{})
+}
+
+// PredictWith is same as Predict, but reports only matches which are
+// specified by opts.
+func (dt *DTree) PredictWith(query string, opts MatchOptions) iter.Seq[Prediction] {
+	dt.ensureFailure()
+	return predict[*DNode](dt, query, opts)
 }
 
-func predict[T comparable](tree predictableTree[T], query string) iter.Seq[Prediction] {
+// PredictWith is same as Predict, but reports only matches which are
+// specified by opts.
+func (st *STree) PredictWith(query string, opts MatchOptions) iter.Seq[Prediction] {
+	return predict[int](st.indexed(), query, opts)
+}
+
+// PredictBytes returns an iterator which enumerates Prediction: key
+// suggestions that match the query in the tree.
+func (dt *DTree) PredictBytes(query []byte) iter.Seq[Prediction] {
+	dt.ensureFailure()
+	return predict[*DNode](dt, query, MatchOptions{})
+}
+
+// PredictBytes returns an iterator which enumerates Prediction: key
+// suggestions that match the query in the tree.
+func (st *STree) PredictBytes(query []byte) iter.Seq[Prediction] {
+	return predict[int](st.indexed(), query, MatchOptions{})
+}
+
+func predict[T comparable, S byteSeq](tree predictableTree[T], query S, opts MatchOptions) iter.Seq[Prediction] {
+	if opts.Kind != Standard {
+		return newLeftmostFinder(tree, query, opts).all()
+	}
 	var zero T
 	tr := newTraverser[T](tree, query)
+	isWord := opts.WordRune
 	return func(yield func(Prediction) bool) {
 		for {
 			node, end, valid := tr.next()
 			if !valid {
 				return
 			}
-			for node != zero {
-				if id := tree.nodeId(node); id > 0 {
-					st := trailingIndex(query[:end], tree.nodeLevel(node))
-					if !yield(Prediction{Start: st, End: end, ID: id}) {
-						tr.close()
-						return
-					}
+			for node = matchedNode(tree, node); node != zero; node = tree.nodeOutput(node) {
+				id := tree.nodeId(node)
+				st := trailingIndex(query[:end], tree.nodeLevel(node), tree.byteLabels())
+				if (isWord == nil || isBoundary(query, st, end, tree.byteLabels(), isWord)) &&
+					!yield(Prediction{Start: st, End: end, ID: id}) {
+					tr.close()
+					return
 				}
-				node = tree.nodeFail(node)
 			}
 		}
 	}
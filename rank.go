@@ -0,0 +1,68 @@
+package trietree
+
+import (
+	"sort"
+)
+
+// CountPrefix returns the number of keys which start with prefix p.
+func (st *STree) CountPrefix(p string) int {
+	x := st.getNode(p)
+	if x < 0 {
+		return 0
+	}
+	counts, _ := st.rankIndex()
+	return counts[x]
+}
+
+// Rank returns the number of keys which are less than k.
+func (st *STree) Rank(k string) int {
+	if len(st.Nodes) == 0 {
+		return 0
+	}
+	counts, offsets := st.rankIndex()
+	rank := 0
+	x := 0
+	for _, r := range decodeLabels(k, st.ByteLabels) {
+		r = st.normalize(r)
+		n := &st.Nodes[x]
+		i := n.Start + sort.Search(n.End-n.Start, func(i int) bool {
+			return st.Nodes[n.Start+i].Label >= r
+		})
+		if i >= n.End {
+			return rank + counts[x]
+		}
+		rank += offsets[i]
+		if st.Nodes[i].Label != r {
+			return rank
+		}
+		x = i
+	}
+	return rank
+}
+
+// Select returns the i'th key (0-origin) in lexicographic order, and its edge
+// ID.  edgeID will be zero when i is out of range.
+func (st *STree) Select(i int) (k string, edgeID int) {
+	if len(st.Nodes) == 0 || i < 0 {
+		return "", 0
+	}
+	counts, offsets := st.rankIndex()
+	if i >= counts[0] {
+		return "", 0
+	}
+	var buf []byte
+	x := 0
+	for {
+		n := &st.Nodes[x]
+		if n.EdgeID > 0 && i == 0 {
+			return string(buf), n.EdgeID
+		}
+		// find the last child which precedes i'th key.
+		c := n.Start + sort.Search(n.End-n.Start, func(j int) bool {
+			return offsets[n.Start+j] > i
+		}) - 1
+		i -= offsets[c]
+		buf = appendLabel(buf, st.Nodes[c].Label, st.ByteLabels)
+		x = c
+	}
+}
@@ -0,0 +1,64 @@
+package trietree_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/koron-go/trietree"
+)
+
+func TestSTree_rank(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "b", "bcd", "あ", "あい", "abd", "ba", "c"}
+	st0 := trietree.Freeze(testDTreePut(t, &trietree.DTree{}, keys...))
+	b := &bytes.Buffer{}
+	if err := st0.Write(b); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	st1, err := trietree.Read(b)
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+
+	// a tree assembled by hand builds indexes for each call.
+	st2 := &trietree.STree{Nodes: st0.Nodes, Levels: st0.Levels}
+	for _, st := range []*trietree.STree{st0, st1, st2} {
+		// CountPrefix
+		for _, p := range []string{"", "a", "ab", "abc", "abcd", "b", "bc", "あ", "z"} {
+			want := 0
+			for _, k := range keys {
+				if strings.HasPrefix(k, p) {
+					want++
+				}
+			}
+			if got := st.CountPrefix(p); got != want {
+				t.Errorf("unexpected CountPrefix(%q): want=%d got=%d", p, want, got)
+			}
+		}
+		// Rank
+		for _, k := range []string{"", "a", "aa", "ab", "abb", "abc", "abcd", "abe", "b", "bb", "bcd", "bd", "c", "d", "あ", "あ0", "あい", "ん"} {
+			want, _ := slices.BinarySearch(sorted, k)
+			if got := st.Rank(k); got != want {
+				t.Errorf("unexpected Rank(%q): want=%d got=%d", k, want, got)
+			}
+		}
+		// Select
+		for i, want := range sorted {
+			got, id := st.Select(i)
+			if got != want {
+				t.Errorf("unexpected Select(%d): want=%q got=%q", i, want, got)
+			}
+			if wantID := slices.Index(keys, want) + 1; id != wantID {
+				t.Errorf("unexpected ID for Select(%d): want=%d got=%d", i, wantID, id)
+			}
+		}
+		for _, i := range []int{-1, len(keys)} {
+			if got, id := st.Select(i); id != 0 {
+				t.Errorf("unexpected Select(%d): %q %d", i, got, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package trietree
+
+import (
+	"iter"
+	"regexp/syntax"
+	"slices"
+)
+
+// MatchRegexp returns an iterator which enumerates keys which match with a
+// compiled regular expression program, and their edge IDs.  Same as
+// regexp.MatchString, a key matches when any part of it matches unless the
+// expression is anchored by '^' or '$'.  Keys are enumerated in lexicographic
+// order.
+//
+// The program can be compiled like this:
+//
+//	re, err := syntax.Parse(expr, syntax.Perl)
+//	...
+//	prog, err := syntax.Compile(re.Simplify())
+func (dt *DTree) MatchRegexp(prog *syntax.Prog) iter.Seq2[string, int] {
+	return matchRegexp[*DNode](dt, prog)
+}
+
+// MatchRegexp returns an iterator which enumerates keys which match with a
+// compiled regular expression program, and their edge IDs.  Same as
+// regexp.MatchString, a key matches when any part of it matches unless the
+// expression is anchored by '^' or '$'.  Keys are enumerated in lexicographic
+// order.
+//
+// The program can be compiled like this:
+//
+//	re, err := syntax.Parse(expr, syntax.Perl)
+//	...
+//	prog, err := syntax.Compile(re.Simplify())
+func (st *STree) MatchRegexp(prog *syntax.Prog) iter.Seq2[string, int] {
+	return matchRegexp[int](st, prog)
+}
+
+func matchRegexp[T comparable](tree searchableTree[T], prog *syntax.Prog) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		rw := regexpWalker[T]{
+			tree:     tree,
+			prog:     prog,
+			anchored: prog.StartCond()&syntax.EmptyBeginText != 0,
+			marks:    make([]uint32, len(prog.Inst)),
+			yield:    yield,
+		}
+		for _, inst := range prog.Inst {
+			if inst.Op == syntax.InstEmptyWidth {
+				rw.hasEmpty = true
+				break
+			}
+		}
+		rw.walk(tree.root(), make([]byte, 0, 16), []uint32{uint32(prog.Start)}, -1)
+	}
+}
+
+// regexpWalker walks a tree with threads of a regular expression program,
+// like a Pike VM.
+type regexpWalker[T comparable] struct {
+	tree     searchableTree[T]
+	prog     *syntax.Prog
+	anchored bool
+	hasEmpty bool
+	yield    func(string, int) bool
+
+	// marks and gen are used to detect visited instructions in closure().
+	marks []uint32
+	gen   uint32
+}
+
+// closure returns instructions which consume a rune or match, reachable from
+// pcs under the context of empty-width assertions.
+func (rw *regexpWalker[T]) closure(pcs []uint32, flag syntax.EmptyOp) []uint32 {
+	rw.gen++
+	if rw.gen == 0 {
+		clear(rw.marks)
+		rw.gen = 1
+	}
+	var out []uint32
+	var add func(pc uint32)
+	add = func(pc uint32) {
+		if rw.marks[pc] == rw.gen {
+			return
+		}
+		rw.marks[pc] = rw.gen
+		inst := &rw.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			add(inst.Out)
+			add(inst.Arg)
+		case syntax.InstCapture, syntax.InstNop:
+			add(inst.Out)
+		case syntax.InstEmptyWidth:
+			if syntax.EmptyOp(inst.Arg)&^flag == 0 {
+				add(inst.Out)
+			}
+		case syntax.InstFail:
+		default:
+			out = append(out, pc)
+		}
+	}
+	for _, pc := range pcs {
+		add(pc)
+	}
+	if !rw.anchored {
+		add(uint32(rw.prog.Start))
+	}
+	return out
+}
+
+// step returns instructions to be continued after consuming r by insts, and
+// whether insts contain a match instruction.
+func (rw *regexpWalker[T]) step(insts []uint32, r rune) (next []uint32, matched bool) {
+	for _, pc := range insts {
+		inst := &rw.prog.Inst[pc]
+		var ok bool
+		switch inst.Op {
+		case syntax.InstMatch:
+			matched = true
+		case syntax.InstRune1:
+			ok = r == inst.Rune[0]
+		case syntax.InstRune:
+			ok = inst.MatchRune(r)
+		case syntax.InstRuneAny:
+			ok = true
+		case syntax.InstRuneAnyNotNL:
+			ok = r != '\n'
+		}
+		if ok {
+			next = append(next, inst.Out)
+		}
+	}
+	return next, matched
+}
+
+// literals returns runes which are acceptable by insts in order, or false when
+// insts accept other runes.
+func (rw *regexpWalker[T]) literals(insts []uint32) ([]rune, bool) {
+	var labels []rune
+	for _, pc := range insts {
+		inst := &rw.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstMatch:
+		case syntax.InstRune1:
+			labels = append(labels, inst.Rune[0])
+		default:
+			return nil, false
+		}
+	}
+	slices.Sort(labels)
+	return slices.Compact(labels), true
+}
+
+// walk visits the node and its descendants with threads pcs.  prev is the
+// label of the node, or -1 for the root.  This returns false when the search
+// should stop.
+func (rw *regexpWalker[T]) walk(node T, buf []byte, pcs []uint32, prev rune) bool {
+	if id := rw.tree.nodeId(node); id > 0 {
+		insts := rw.closure(pcs, syntax.EmptyOpContext(prev, -1))
+		if _, matched := rw.step(insts, -1); matched {
+			if !rw.yield(string(buf), id) {
+				return false
+			}
+		}
+	}
+	visit := func(r rune, child T) bool {
+		insts := rw.closure(pcs, syntax.EmptyOpContext(prev, r))
+		next, matched := rw.step(insts, r)
+		if matched {
+			// all keys under the child match.
+			return eachKey(rw.tree, child, appendLabel(buf, r, rw.tree.byteLabels()), rw.yield)
+		}
+		if len(next) == 0 && rw.anchored {
+			return true
+		}
+		return rw.walk(child, appendLabel(buf, r, rw.tree.byteLabels()), next, r)
+	}
+	// visit only children which have acceptable labels, when possible.
+	if rw.anchored && !rw.hasEmpty {
+		if labels, ok := rw.literals(rw.closure(pcs, 0)); ok {
+			for _, r := range labels {
+				if child, ok := rw.tree.child(node, r); ok {
+					if !visit(r, child) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+	}
+	for r, child := range rw.tree.children(node) {
+		if !visit(r, child) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package trietree_test
+
+import (
+	"iter"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+type regexpMatcher interface {
+	MatchRegexp(prog *syntax.Prog) iter.Seq2[string, int]
+}
+
+func compileProg(t *testing.T, expr string) *syntax.Prog {
+	t.Helper()
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %s", expr, err)
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		t.Fatalf("failed to compile %q: %s", expr, err)
+	}
+	return prog
+}
+
+func testMatchRegexp(t *testing.T, rm regexpMatcher, keys []string) {
+	t.Helper()
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	for _, expr := range []string{
+		`^ca.$`, `tion$`, `^c.*t$`, `a`, `^a(b|x)`, `^$`, `^(?i)CA[rt]`,
+		`\bion\b`, `t\B`, `^[a-c]+$`, `^.?$`, `^あ.*`, `い$`, `^x`, `b.*c`,
+	} {
+		re := regexp.MustCompile(expr)
+		want := []keyID{}
+		for _, k := range sorted {
+			if re.MatchString(k) {
+				want = append(want, keyID{Key: k, ID: slices.Index(keys, k) + 1})
+			}
+		}
+		got := collectKeyIDs(rm.MatchRegexp(compileProg(t, expr)))
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("unexpected matches for %q: -want +got\n%s", expr, d)
+		}
+	}
+}
+
+func TestMatchRegexp(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "aXbYc", "b", "cat", "cut", "cart", "car", "ct", "nation", "station", "tion", "ion x", "あ", "あい"}
+	t.Run("dynamic", func(t *testing.T) {
+		testMatchRegexp(t, testDTreePut(t, &trietree.DTree{}, keys...), keys)
+	})
+	t.Run("static", func(t *testing.T) {
+		testMatchRegexp(t, trietree.Freeze(testDTreePut(t, &trietree.DTree{}, keys...)), keys)
+	})
+}
@@ -0,0 +1,151 @@
+package trietree
+
+import (
+	"io"
+	"strings"
+)
+
+// ReplaceAll returns a copy of s, which non-overlapping matches of keys are
+// replaced with results of fn.  Matches are found with LeftmostFirst.
+func (dt *DTree) ReplaceAll(s string, fn func(Prediction) string) string {
+	return dt.ReplaceAllWith(s, MatchOptions{}, fn)
+}
+
+// ReplaceAll returns a copy of s, which non-overlapping matches of keys are
+// replaced with results of fn.  Matches are found with LeftmostFirst.
+func (st *STree) ReplaceAll(s string, fn func(Prediction) string) string {
+	return st.ReplaceAllWith(s, MatchOptions{}, fn)
+}
+
+// ReplaceAllWith is same as ReplaceAll, but matches are found with opts.
+// LeftmostFirst is used when opts.Kind is Standard.
+func (dt *DTree) ReplaceAllWith(s string, opts MatchOptions, fn func(Prediction) string) string {
+	dt.ensureFailure()
+	return replaceAll[*DNode](dt, s, opts, fn)
+}
+
+// ReplaceAllWith is same as ReplaceAll, but matches are found with opts.
+// LeftmostFirst is used when opts.Kind is Standard.
+func (st *STree) ReplaceAllWith(s string, opts MatchOptions, fn func(Prediction) string) string {
+	return replaceAll[int](st.indexed(), s, opts, fn)
+}
+
+func replaceAll[T comparable](tree predictableTree[T], s string, opts MatchOptions, fn func(Prediction) string) string {
+	if opts.Kind == Standard {
+		opts.Kind = LeftmostFirst
+	}
+	var b strings.Builder
+	last := 0
+	for p := range newLeftmostFinder(tree, s, opts).all() {
+		b.WriteString(s[last:p.Start])
+		b.WriteString(fn(p))
+		last = p.End
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// Replacer replaces a list of strings with replacements, same as
+// strings.Replacer.  It is backed by STree, so it is efficient even with many
+// old strings.  It is safe for concurrent use by multiple goroutines.
+type Replacer struct {
+	tree *STree
+	news []string
+
+	// hasEmpty indicates an empty old string is given.  It matches at every
+	// position, where no old strings which precede it in arguments match.
+	hasEmpty bool
+	empty    string
+	// emptyRank is the number of old strings which precede the empty one.
+	emptyRank int
+}
+
+// NewReplacer returns a new Replacer from a list of old, new string pairs.
+// Replacements are performed in the order they appear in the target string,
+// without overlapping matches.  The old string comparisons are done in
+// argument order.
+//
+// NewReplacer panics if given an odd number of arguments.
+func NewReplacer(oldnew ...string) *Replacer {
+	if len(oldnew)%2 == 1 {
+		panic("trietree.NewReplacer: odd argument count")
+	}
+	r := &Replacer{}
+	dt := &DTree{ByteLabels: true}
+	for i := 0; i < len(oldnew); i += 2 {
+		old, new := oldnew[i], oldnew[i+1]
+		if old == "" {
+			if !r.hasEmpty {
+				r.hasEmpty = true
+				r.empty = new
+				r.emptyRank = len(r.news)
+			}
+			continue
+		}
+		// the first one wins when old strings are duplicated.
+		if id := dt.Put(old); id > len(r.news) {
+			r.news = append(r.news, new)
+		}
+	}
+	r.tree = Freeze(dt)
+	return r
+}
+
+// Replace returns a copy of s with all replacements performed.
+func (r *Replacer) Replace(s string) string {
+	var b strings.Builder
+	r.WriteString(&b, s)
+	return b.String()
+}
+
+// WriteString writes s to w with all replacements performed.
+func (r *Replacer) WriteString(w io.Writer, s string) (n int, err error) {
+	write := func(s string) bool {
+		if s == "" {
+			return true
+		}
+		var wn int
+		wn, err = io.WriteString(w, s)
+		n += wn
+		return err == nil
+	}
+	lf := newLeftmostFinder[int](r.tree, s, MatchOptions{Kind: LeftmostFirst})
+	p, _, found := lf.next()
+	last := 0
+	prevEmpty := false
+	for i := 0; i <= len(s); {
+		var (
+			val    string
+			keylen int
+			ok     bool
+		)
+		if found && p.Start == i {
+			val, keylen, ok = r.news[p.ID-1], p.End-p.Start, true
+			if r.hasEmpty && !prevEmpty && p.ID > r.emptyRank {
+				val, keylen = r.empty, 0
+			}
+		}
+		if !ok && r.hasEmpty && !prevEmpty {
+			val, keylen, ok = r.empty, 0, true
+		}
+		// ignore the empty match at the next time, to make progress.
+		prevEmpty = ok && keylen == 0
+		if !ok {
+			i++
+			continue
+		}
+		if !write(s[last:i]) || !write(val) {
+			return n, err
+		}
+		if keylen > 0 {
+			i += keylen
+			p, _, found = lf.next()
+		}
+		last = i
+	}
+	write(s[last:])
+	return n, err
+}
@@ -0,0 +1,84 @@
+package trietree_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koron-go/trietree"
+)
+
+func TestReplaceAll(t *testing.T) {
+	fn := func(p trietree.Prediction) string {
+		return strings.Repeat("*", p.End-p.Start)
+	}
+	for _, c := range []struct {
+		kind trietree.MatchKind
+		s    string
+		want string
+	}{
+		{trietree.Standard, "abcdxc", "***dx*"},
+		{trietree.LeftmostFirst, "abcdxc", "***dx*"},
+		{trietree.LeftmostLongest, "abcdxc", "****x*"},
+		{trietree.Standard, "xyz", "xyz"},
+		{trietree.Standard, "", ""},
+	} {
+		dt := testDTreePut(t, &trietree.DTree{}, "ab", "abcd", "c")
+		for _, tree := range []interface {
+			ReplaceAllWith(string, trietree.MatchOptions, func(trietree.Prediction) string) string
+		}{dt, trietree.Freeze(dt)} {
+			if got := tree.ReplaceAllWith(c.s, trietree.MatchOptions{Kind: c.kind}, fn); got != c.want {
+				t.Errorf("unexpected ReplaceAll for %T with kind=%d: want=%q got=%q", tree, c.kind, c.want, got)
+			}
+		}
+	}
+}
+
+func TestReplacer(t *testing.T) {
+	for i, oldnew := range [][]string{
+		{},
+		{"a", "A"},
+		{"a", "1", "a", "2", "ab", "3"},
+		{"ab", "3", "a", "1"},
+		{"", "X", "a", "A"},
+		{"a", "A", "", "X"},
+		{"", "X", "", "Y", "b", "B"},
+		{"あ", "a", "い", "i", "あい", "ai"},
+		{"aaa", "3", "aa", "2", "a", "1"},
+		{"\xe3", "?", "b", "c"},
+	} {
+		want := strings.NewReplacer(oldnew...)
+		got := trietree.NewReplacer(oldnew...)
+		for _, s := range []string{"", "a", "ab", "abab", "aあb", "あいう", "aaaaa", "xyz"} {
+			w, g := want.Replace(s), got.Replace(s)
+			if g != w {
+				t.Errorf("unexpected #%d %q for %q: want=%q got=%q", i, oldnew, s, w, g)
+			}
+			var b strings.Builder
+			n, err := got.WriteString(&b, s)
+			if err != nil {
+				t.Fatalf("WriteString failed: %s", err)
+			}
+			if b.String() != w || n != len(w) {
+				t.Errorf("unexpected WriteString #%d %q for %q: want=%q got=%q (%d)", i, oldnew, s, w, b.String(), n)
+			}
+		}
+	}
+}
+
+func BenchmarkReplaceAll_longKey(b *testing.B) {
+	st := trietree.Freeze(newLongKeyTree())
+	b.ResetTimer()
+	for range b.N {
+		st.ReplaceAll(longKeyQuery, func(trietree.Prediction) string {
+			return "b"
+		})
+	}
+}
+
+func BenchmarkReplacer_longKey(b *testing.B) {
+	r := trietree.NewReplacer(strings.Repeat("a", 2000)+"b", "x", "a", "b")
+	b.ResetTimer()
+	for range b.N {
+		r.Replace(longKeyQuery)
+	}
+}
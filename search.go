@@ -0,0 +1,317 @@
+package trietree
+
+import (
+	"iter"
+	"sort"
+)
+
+type searchableTree[T comparable] interface {
+	root() T
+	byteLabels() bool
+	normalize(rune) rune
+	child(T, rune) (T, bool)
+	children(T) iter.Seq2[rune, T]
+	childrenFrom(T, rune) iter.Seq2[rune, T]
+	nodeId(T) int
+}
+
+// methods DTree satisfies searchableTree[*DNode]
+func (dt *DTree) child(n *DNode, r rune) (*DNode, bool) {
+	c := n.Get(r)
+	return c, c != nil
+}
+
+func (dt *DTree) children(n *DNode) iter.Seq2[rune, *DNode] {
+	return func(yield func(rune, *DNode) bool) {
+		n.Child.siblings(yield)
+	}
+}
+
+func (dt *DTree) childrenFrom(n *DNode, from rune) iter.Seq2[rune, *DNode] {
+	return func(yield func(rune, *DNode) bool) {
+		n.Child.siblingsFrom(from, yield)
+	}
+}
+
+// siblings enumerates sibling nodes in order of Label, same as eachSiblings.
+// But this stops when yield returns false.
+func (dn *DNode) siblings(yield func(rune, *DNode) bool) bool {
+	if dn == nil {
+		return true
+	}
+	return dn.Low.siblings(yield) && yield(dn.Label, dn) && dn.High.siblings(yield)
+}
+
+// siblingsFrom enumerates sibling nodes which have Label greater than or equal
+// to from, in order of Label.
+func (dn *DNode) siblingsFrom(from rune, yield func(rune, *DNode) bool) bool {
+	if dn == nil {
+		return true
+	}
+	if dn.Label < from {
+		return dn.High.siblingsFrom(from, yield)
+	}
+	return dn.Low.siblingsFrom(from, yield) && yield(dn.Label, dn) && dn.High.siblings(yield)
+}
+
+// methods STree satisfies searchableTree[int]
+func (st *STree) child(x int, r rune) (int, bool) {
+	n := &st.Nodes[x]
+	c := st.find(n.Start, n.End, r)
+	return c, c >= 0
+}
+
+func (st *STree) children(x int) iter.Seq2[rune, int] {
+	return func(yield func(rune, int) bool) {
+		n := &st.Nodes[x]
+		for i := n.Start; i < n.End; i++ {
+			if !yield(st.Nodes[i].Label, i) {
+				return
+			}
+		}
+	}
+}
+
+func (st *STree) childrenFrom(x int, from rune) iter.Seq2[rune, int] {
+	return func(yield func(rune, int) bool) {
+		n := &st.Nodes[x]
+		a, b := n.Start, n.End
+		i := a + sort.Search(b-a, func(i int) bool {
+			return st.Nodes[a+i].Label >= from
+		})
+		for ; i < b; i++ {
+			if !yield(st.Nodes[i].Label, i) {
+				return
+			}
+		}
+	}
+}
+
+// CommonPrefixes returns an iterator which enumerates all keys which are
+// prefixes of s, from shorter to longer. Start of each Prediction is always
+// zero.
+func (dt *DTree) CommonPrefixes(s string) iter.Seq[Prediction] {
+	return commonPrefixes[*DNode](dt, s)
+}
+
+// CommonPrefixes returns an iterator which enumerates all keys which are
+// prefixes of s, from shorter to longer. Start of each Prediction is always
+// zero.
+func (st *STree) CommonPrefixes(s string) iter.Seq[Prediction] {
+	return commonPrefixes[int](st, s)
+}
+
+func commonPrefixes[T comparable](tree searchableTree[T], s string) iter.Seq[Prediction] {
+	return func(yield func(Prediction) bool) {
+		node := tree.root()
+		end := 0
+		for end < len(s) {
+			r, sz := decodeLabel(s[end:], tree.byteLabels())
+			next, ok := tree.child(node, tree.normalize(r))
+			if !ok {
+				return
+			}
+			end += sz
+			if id := tree.nodeId(next); id > 0 {
+				if !yield(Prediction{Start: 0, End: end, ID: id}) {
+					return
+				}
+			}
+			node = next
+		}
+	}
+}
+
+// PrefixSearch returns an iterator which enumerates all keys which start with
+// prefix, and their edge IDs.  Keys are enumerated in lexicographic order.
+func (dt *DTree) PrefixSearch(prefix string) iter.Seq2[string, int] {
+	return prefixSearch[*DNode](dt, prefix)
+}
+
+// PrefixSearch returns an iterator which enumerates all keys which start with
+// prefix, and their edge IDs.  Keys are enumerated in lexicographic order.
+func (st *STree) PrefixSearch(prefix string) iter.Seq2[string, int] {
+	return prefixSearch[int](st, prefix)
+}
+
+func prefixSearch[T comparable](tree searchableTree[T], prefix string) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		node := tree.root()
+		buf := make([]byte, 0, len(prefix)+16)
+		for _, r := range decodeLabels(prefix, tree.byteLabels()) {
+			r = tree.normalize(r)
+			next, ok := tree.child(node, r)
+			if !ok {
+				return
+			}
+			buf = appendLabel(buf, r, tree.byteLabels())
+			node = next
+		}
+		eachKey(tree, node, buf, yield)
+	}
+}
+
+// eachKey enumerates all keys under the node in lexicographic order.
+// buf should hold the key of the node.
+func eachKey[T comparable](tree searchableTree[T], node T, buf []byte, yield func(string, int) bool) bool {
+	if id := tree.nodeId(node); id > 0 {
+		if !yield(string(buf), id) {
+			return false
+		}
+	}
+	for r, child := range tree.children(node) {
+		if !eachKey(tree, child, appendLabel(buf, r, tree.byteLabels()), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// All returns an iterator which enumerates all keys in the tree, and their
+// edge IDs.  Keys are enumerated in lexicographic order.
+func (dt *DTree) All() iter.Seq2[string, int] {
+	return all[*DNode](dt)
+}
+
+// All returns an iterator which enumerates all keys in the tree, and their
+// edge IDs.  Keys are enumerated in lexicographic order.
+func (st *STree) All() iter.Seq2[string, int] {
+	return all[int](st)
+}
+
+func all[T comparable](tree searchableTree[T]) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		eachKey(tree, tree.root(), make([]byte, 0, 16), yield)
+	}
+}
+
+// BoundKind specifies how Bound treats its key.
+type BoundKind int
+
+const (
+	// Unbounded means no bound.  Key of Bound is ignored.
+	Unbounded BoundKind = iota
+
+	// Included means the key itself is in the range.
+	Included
+
+	// Excluded means the key itself is not in the range.
+	Excluded
+)
+
+// Bound is a lower or upper bound of a range of keys.  The zero value is
+// unbounded.
+type Bound struct {
+	Key  string
+	Kind BoundKind
+}
+
+// Include returns a Bound which includes k.
+func Include(k string) Bound {
+	return Bound{Key: k, Kind: Included}
+}
+
+// Exclude returns a Bound which excludes k.
+func Exclude(k string) Bound {
+	return Bound{Key: k, Kind: Excluded}
+}
+
+// rangeBounds converts arguments of Range to Bounds.
+func rangeBounds(lo, hi string) (Bound, Bound) {
+	if hi == "" {
+		return Include(lo), Bound{}
+	}
+	return Include(lo), Exclude(hi)
+}
+
+// Range returns an iterator which enumerates keys between lo (inclusive) and
+// hi (exclusive), and their edge IDs.  Keys are enumerated in lexicographic
+// order.  An empty hi means no upper bound.  Use RangeBounds for other kinds
+// of bounds.
+func (dt *DTree) Range(lo, hi string) iter.Seq2[string, int] {
+	return dt.RangeBounds(rangeBounds(lo, hi))
+}
+
+// RangeBounds returns an iterator which enumerates keys between lo and hi,
+// and their edge IDs.  Keys are enumerated in lexicographic order.
+func (dt *DTree) RangeBounds(lo, hi Bound) iter.Seq2[string, int] {
+	return rangeKeys[*DNode](dt, lo, hi)
+}
+
+// Range returns an iterator which enumerates keys between lo (inclusive) and
+// hi (exclusive), and their edge IDs.  Keys are enumerated in lexicographic
+// order.  An empty hi means no upper bound.  Use RangeBounds for other kinds
+// of bounds.
+func (st *STree) Range(lo, hi string) iter.Seq2[string, int] {
+	return st.RangeBounds(rangeBounds(lo, hi))
+}
+
+// RangeBounds returns an iterator which enumerates keys between lo and hi,
+// and their edge IDs.  Keys are enumerated in lexicographic order.
+func (st *STree) RangeBounds(lo, hi Bound) iter.Seq2[string, int] {
+	return rangeKeys[int](st, lo, hi)
+}
+
+func rangeKeys[T comparable](tree searchableTree[T], lo, hi Bound) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		rw := rangeWalker[T]{
+			tree:   tree,
+			lo:     decodeLabels(lo.Key, tree.byteLabels()),
+			hi:     decodeLabels(hi.Key, tree.byteLabels()),
+			loExcl: lo.Kind == Excluded,
+			hiIncl: hi.Kind == Included,
+			yield:  yield,
+		}
+		for i, r := range rw.lo {
+			rw.lo[i] = tree.normalize(r)
+		}
+		for i, r := range rw.hi {
+			rw.hi[i] = tree.normalize(r)
+		}
+		rw.walk(tree.root(), make([]byte, 0, 16), 0, lo.Kind != Unbounded, hi.Kind != Unbounded)
+	}
+}
+
+type rangeWalker[T comparable] struct {
+	tree   searchableTree[T]
+	lo     []rune
+	hi     []rune
+	loExcl bool // loExcl excludes lo itself.
+	hiIncl bool // hiIncl includes hi itself.
+	yield  func(string, int) bool
+}
+
+// walk enumerates keys under the node at depth d.  tiedLo (tiedHi) tells
+// the key of the node equals to the first d runes of lo (hi).  This returns
+// false when the enumeration should stop.
+func (rw *rangeWalker[T]) walk(node T, buf []byte, d int, tiedLo, tiedHi bool) bool {
+	atHi := tiedHi && d == len(rw.hi)
+	if atHi && !rw.hiIncl {
+		// this node and all following nodes are not less than hi.
+		return false
+	}
+	underLo := tiedLo && d < len(rw.lo)
+	atLo := tiedLo && d == len(rw.lo)
+	if id := rw.tree.nodeId(node); id > 0 && !underLo && !(atLo && rw.loExcl) {
+		if !rw.yield(string(buf), id) {
+			return false
+		}
+	}
+	if atHi {
+		// all following nodes are greater than hi.
+		return false
+	}
+	var from rune
+	if underLo {
+		from = rw.lo[d]
+	}
+	for r, child := range rw.tree.childrenFrom(node, from) {
+		if tiedHi && r > rw.hi[d] {
+			return false
+		}
+		if !rw.walk(child, appendLabel(buf, r, rw.tree.byteLabels()), d+1, underLo && r == rw.lo[d], tiedHi && r == rw.hi[d]) {
+			return false
+		}
+	}
+	return true
+}
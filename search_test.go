@@ -0,0 +1,243 @@
+package trietree_test
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+type commonPrefixer interface {
+	CommonPrefixes(string) iter.Seq[trietree.Prediction]
+}
+
+func testCommonPrefixes(t *testing.T, cp commonPrefixer) {
+	t.Helper()
+	for i, c := range []struct {
+		q    string
+		want []prediction
+	}{
+		{"abcd", []prediction{
+			{Start: 0, End: 1, ID: 1, Key: "a"},
+			{Start: 0, End: 2, ID: 2, Key: "ab"},
+			{Start: 0, End: 3, ID: 3, Key: "abc"},
+		}},
+		{"ab", []prediction{
+			{Start: 0, End: 1, ID: 1, Key: "a"},
+			{Start: 0, End: 2, ID: 2, Key: "ab"},
+		}},
+		{"bc", []prediction{
+			{Start: 0, End: 1, ID: 4, Key: "b"},
+		}},
+		{"あいう", []prediction{
+			{Start: 0, End: 3, ID: 6, Key: "あ"},
+			{Start: 0, End: 6, ID: 7, Key: "あい"},
+		}},
+		{"cab", []prediction{}},
+		{"", []prediction{}},
+	} {
+		got := make([]prediction, 0, 10)
+		for p := range cp.CommonPrefixes(c.q) {
+			got = append(got, prediction{
+				Start: p.Start,
+				End:   p.End,
+				ID:    p.ID,
+				Key:   c.q[p.Start:p.End],
+			})
+		}
+		if d := cmp.Diff(c.want, got); d != "" {
+			t.Errorf("unexpected #%d %q: -want +got\n%s", i, c.q, d)
+		}
+	}
+}
+
+func TestCommonPrefixes(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "b", "bcd", "あ", "あい"}
+	t.Run("dynamic", func(t *testing.T) {
+		testCommonPrefixes(t, testDTreePut(t, &trietree.DTree{}, keys...))
+	})
+	t.Run("static", func(t *testing.T) {
+		testCommonPrefixes(t, trietree.Freeze(testDTreePut(t, &trietree.DTree{}, keys...)))
+	})
+}
+
+type keyID struct {
+	Key string
+	ID  int
+}
+
+type prefixSearcher interface {
+	PrefixSearch(string) iter.Seq2[string, int]
+}
+
+func collectKeyIDs(seq iter.Seq2[string, int]) []keyID {
+	got := make([]keyID, 0, 10)
+	for k, id := range seq {
+		got = append(got, keyID{Key: k, ID: id})
+	}
+	return got
+}
+
+func testPrefixSearch(t *testing.T, ps prefixSearcher) {
+	t.Helper()
+	for i, c := range []struct {
+		prefix string
+		want   []keyID
+	}{
+		{"", []keyID{
+			{"a", 1}, {"ab", 2}, {"abc", 3}, {"abd", 8}, {"b", 4}, {"bcd", 5},
+			{"あ", 6}, {"あい", 7},
+		}},
+		{"a", []keyID{{"a", 1}, {"ab", 2}, {"abc", 3}, {"abd", 8}}},
+		{"ab", []keyID{{"ab", 2}, {"abc", 3}, {"abd", 8}}},
+		{"bc", []keyID{{"bcd", 5}}},
+		{"あ", []keyID{{"あ", 6}, {"あい", 7}}},
+		{"abcd", []keyID{}},
+		{"c", []keyID{}},
+	} {
+		got := collectKeyIDs(ps.PrefixSearch(c.prefix))
+		if d := cmp.Diff(c.want, got); d != "" {
+			t.Errorf("unexpected #%d %q: -want +got\n%s", i, c.prefix, d)
+		}
+	}
+	// stop iteration in the middle.
+	n := 0
+	for range ps.PrefixSearch("a") {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("unexpected iteration count: %d", n)
+	}
+}
+
+func TestPrefixSearch(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "b", "bcd", "あ", "あい", "abd"}
+	t.Run("dynamic", func(t *testing.T) {
+		testPrefixSearch(t, testDTreePut(t, &trietree.DTree{}, keys...))
+	})
+	t.Run("static", func(t *testing.T) {
+		testPrefixSearch(t, trietree.Freeze(testDTreePut(t, &trietree.DTree{}, keys...)))
+	})
+}
+
+type allIterator interface {
+	All() iter.Seq2[string, int]
+}
+
+func TestAll(t *testing.T) {
+	keys := []string{"bcd", "あい", "ab", "a", "abc", "b", "あ", "abd"}
+	want := []keyID{
+		{"a", 4}, {"ab", 3}, {"abc", 5}, {"abd", 8}, {"b", 6}, {"bcd", 1},
+		{"あ", 7}, {"あい", 2},
+	}
+	dt := testDTreePut(t, &trietree.DTree{}, keys...)
+	for _, tree := range []allIterator{dt, trietree.Freeze(dt)} {
+		got := collectKeyIDs(tree.All())
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("unexpected %T: -want +got\n%s", tree, d)
+		}
+	}
+	empty := &trietree.DTree{}
+	for _, tree := range []allIterator{empty, trietree.Freeze(empty)} {
+		if got := collectKeyIDs(tree.All()); len(got) != 0 {
+			t.Errorf("unexpected keys in empty %T: %+v", tree, got)
+		}
+	}
+}
+
+type ranger interface {
+	Range(lo, hi string) iter.Seq2[string, int]
+	RangeBounds(lo, hi trietree.Bound) iter.Seq2[string, int]
+}
+
+// inBounds checks k is in the range between lo and hi.
+func inBounds(k string, lo, hi trietree.Bound) bool {
+	switch c := strings.Compare(k, lo.Key); lo.Kind {
+	case trietree.Included:
+		if c < 0 {
+			return false
+		}
+	case trietree.Excluded:
+		if c <= 0 {
+			return false
+		}
+	}
+	switch c := strings.Compare(k, hi.Key); hi.Kind {
+	case trietree.Included:
+		if c > 0 {
+			return false
+		}
+	case trietree.Excluded:
+		if c >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func testRange(t *testing.T, rg ranger, keys []string) {
+	t.Helper()
+	rangeKeys := func(lo, hi trietree.Bound) []keyID {
+		want := make([]keyID, 0, 10)
+		for i, k := range keys {
+			if inBounds(k, lo, hi) {
+				want = append(want, keyID{Key: k, ID: i + 1})
+			}
+		}
+		slices.SortFunc(want, func(a, b keyID) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+		return want
+	}
+	bounds := []string{"", "a", "aa", "ab", "abc", "abcd", "abd", "b", "ba", "bc", "bcd", "c", "z", "あ", "あい", "ab\x00", "\x00"}
+	kinds := []trietree.BoundKind{trietree.Unbounded, trietree.Included, trietree.Excluded}
+	for _, lo := range bounds {
+		for _, hi := range bounds {
+			// Range is [lo, hi), and an empty hi is unbounded.
+			wantHi := trietree.Exclude(hi)
+			if hi == "" {
+				wantHi = trietree.Bound{}
+			}
+			want := rangeKeys(trietree.Include(lo), wantHi)
+			if d := cmp.Diff(want, collectKeyIDs(rg.Range(lo, hi))); d != "" {
+				t.Errorf("unexpected range [%q, %q): -want +got\n%s", lo, hi, d)
+			}
+			for _, loKind := range kinds {
+				for _, hiKind := range kinds {
+					lb := trietree.Bound{Key: lo, Kind: loKind}
+					hb := trietree.Bound{Key: hi, Kind: hiKind}
+					want := rangeKeys(lb, hb)
+					if d := cmp.Diff(want, collectKeyIDs(rg.RangeBounds(lb, hb))); d != "" {
+						t.Errorf("unexpected range %+v %+v: -want +got\n%s", lb, hb, d)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "b", "bcd", "あ", "あい", "abd", "ba", "c", ""}
+	t.Run("dynamic", func(t *testing.T) {
+		testRange(t, testDTreePut(t, &trietree.DTree{}, keys...), keys)
+	})
+	t.Run("static", func(t *testing.T) {
+		testRange(t, trietree.Freeze(testDTreePut(t, &trietree.DTree{}, keys...)), keys)
+	})
+	t.Run("normalized", func(t *testing.T) {
+		dt := testDTreePut(t, &trietree.DTree{Normalizer: unicode.ToLower}, "Kelvin", "cat")
+		for _, rg := range []ranger{dt, trietree.Freeze(dt)} {
+			want := []keyID{{"cat", 2}}
+			if d := cmp.Diff(want, collectKeyIDs(rg.Range("A", "D"))); d != "" {
+				t.Errorf("unexpected range for %T: -want +got\n%s", rg, d)
+			}
+		}
+	})
+}
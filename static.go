@@ -3,16 +3,50 @@ package trietree
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"math"
+	"slices"
 	"sort"
-	"unicode/utf8"
 )
 
 // STree is static tree. It is optimized for serialization.
+//
+// Freeze and Read build indexes which are derived from Nodes and Levels, like
+// output links of the Aho-Corasick algorithm.  When STree is assembled from
+// Nodes and Levels by hand, Fail of each node must be filled.  Such a tree
+// lacks the indexes, so scans walk Fail instead of output links, and some
+// methods build the indexes for each call.
 type STree struct {
 	Nodes  []SNode
 	Levels []int
+
+	// Normalizer is applied to queries when not nil.  It is not serialized,
+	// so set same one with the original DTree after Read.
+	Normalizer Normalizer
+
+	// ByteLabels makes each byte of queries a label, instead of each rune
+	// decoded from UTF-8.  It is copied from DTree by Freeze, and serialized.
+	ByteLabels bool
+
+	// parents is an index to find the parent of each node.
+	parents []int
+	// edges is an index to find edge nodes from its ID.
+	edges []int
+	// counts is the number of keys under each node.
+	counts []int
+	// offsets is the number of keys which precede each node in the subtree
+	// of its parent.
+	offsets []int
+
+	// outputs is an index to the nearest failure node which has EdgeID for
+	// each node, or 0.  It is derived from Fail of Nodes.
+	outputs []int
+	// depths is the depth of each node.
+	depths []int
+
+	// dfa is a table of transitions which is built by CompileDFA.
+	dfa *dfa
 }
 
 // Freeze converts dynamic tree to static tree.
@@ -51,27 +85,144 @@ func Freeze(src *DTree) *STree {
 
 	procNode(0, &src.Root, 0)
 	st := &STree{
-		Nodes:  nodes,
-		Levels: levels,
+		Nodes:      nodes,
+		Levels:     levels,
+		Normalizer: src.Normalizer,
+		ByteLabels: src.ByteLabels,
 	}
-	st.fillFailure(0)
+	st.fillFailure()
+	st.buildIndex()
 
 	return st
 }
 
-func (st *STree) fillFailure(x int) {
-	p := &st.Nodes[x]
-	if p.Start == 0 {
-		return
+// buildIndex builds indexes which are derived from Nodes and Levels.
+func (st *STree) buildIndex() {
+	if st.outputs == nil {
+		st.outputs = st.buildOutputs()
+	}
+	st.parents, st.edges = st.buildKeyIndex()
+	st.counts, st.offsets = st.buildRankIndex()
+	st.depths = st.buildDepths()
+}
+
+// indexed returns st itself when it has indexes, otherwise a copy of st with
+// indexes, for a tree assembled by hand.
+func (st *STree) indexed() *STree {
+	if st.depths != nil {
+		return st
 	}
-	for i := p.Start; i < p.End; i++ {
-		c := &st.Nodes[i]
-		c.Fail = st.nextNode(p.Fail, c.Label)
-		if c.Fail == i {
-			c.Fail = 0
+	c := *st
+	c.buildIndex()
+	return &c
+}
+
+func (st *STree) buildDepths() []int {
+	// children are always placed after its parent.
+	depths := make([]int, len(st.Nodes))
+	for x, n := range st.Nodes {
+		for i := n.Start; i < n.End; i++ {
+			depths[i] = depths[x] + 1
 		}
-		st.fillFailure(i)
 	}
+	return depths
+}
+
+// keyIndex returns parents and edges.  Those are built by Freeze and Read,
+// or built for each call when the tree is assembled by hand.
+func (st *STree) keyIndex() (parents, edges []int) {
+	if st.parents != nil {
+		return st.parents, st.edges
+	}
+	return st.buildKeyIndex()
+}
+
+func (st *STree) buildKeyIndex() (parents, edges []int) {
+	parents = make([]int, len(st.Nodes))
+	edges = make([]int, len(st.Levels))
+	for i := range edges {
+		edges[i] = -1
+	}
+	if len(parents) > 0 {
+		parents[0] = -1
+	}
+	for x, n := range st.Nodes {
+		for i := n.Start; i < n.End; i++ {
+			parents[i] = x
+		}
+		if n.EdgeID > 0 && n.EdgeID <= len(edges) {
+			edges[n.EdgeID-1] = x
+		}
+	}
+	return parents, edges
+}
+
+// rankIndex returns counts and offsets.  Those are built by Freeze and Read,
+// or built for each call when the tree is assembled by hand.
+func (st *STree) rankIndex() (counts, offsets []int) {
+	if st.counts != nil {
+		return st.counts, st.offsets
+	}
+	return st.buildRankIndex()
+}
+
+func (st *STree) buildRankIndex() (counts, offsets []int) {
+	// count keys from bottom, children are always placed after its parent.
+	counts = make([]int, len(st.Nodes))
+	offsets = make([]int, len(st.Nodes))
+	for x := len(st.Nodes) - 1; x >= 0; x-- {
+		n := &st.Nodes[x]
+		c := 0
+		if n.EdgeID > 0 {
+			c++
+		}
+		for i := n.Start; i < n.End; i++ {
+			offsets[i] = c
+			c += counts[i]
+		}
+		counts[x] = c
+	}
+	return counts, offsets
+}
+
+// fillFailure fills Fail fields in breadth first order.
+func (st *STree) fillFailure() {
+	queue := []int{0}
+	for len(queue) > 0 {
+		p := &st.Nodes[queue[0]]
+		queue = queue[1:]
+		for i := p.Start; i < p.End; i++ {
+			c := &st.Nodes[i]
+			c.Fail = st.nextNode(p.Fail, c.Label)
+			if c.Fail == i {
+				c.Fail = 0
+			}
+			queue = append(queue, i)
+		}
+	}
+}
+
+// buildOutputs builds outputs in breadth first order, as the output of a
+// node depends on the output of its failure node.
+func (st *STree) buildOutputs() []int {
+	outputs := make([]int, len(st.Nodes))
+	if len(st.Nodes) == 0 {
+		return outputs
+	}
+	queue := []int{0}
+	for len(queue) > 0 {
+		p := &st.Nodes[queue[0]]
+		queue = queue[1:]
+		for i := p.Start; i < p.End; i++ {
+			if f := st.Nodes[i].Fail; f > 0 && st.Nodes[f].EdgeID > 0 {
+				outputs[i] = f
+			} else {
+				outputs[i] = outputs[f]
+			}
+			queue = append(queue, i)
+		}
+	}
+	return outputs
 }
 
 // Scan scans a string to find matched words.
@@ -82,32 +233,32 @@ func (st *STree) Scan(s string, r ScanReporter) error {
 // ScanContext scans a string to find matched words.
 // ScanReporter r will receive reports for each characters when scan.
 func (st *STree) ScanContext(ctx context.Context, s string, r ScanReporter) error {
-	sr := newScanReport(r, len(s))
-	curr := 0
-	for i, c := range s {
-		next := st.nextNode(curr, c)
-		// emit a scan event.
-		sr.reset(i, c)
-		for n := next; n > 0; n = st.Nodes[n].Fail {
-			if edge := st.Nodes[n].EdgeID; edge > 0 {
-				lv := -1
-				if edge-1 < len(st.Levels) {
-					lv = st.Levels[edge-1]
-				}
-				sr.add(edge, lv)
-			}
-		}
-		sr.emit()
-		// prepare for next.
-		if err := ctx.Err(); err != nil {
-			return err
-		}
-		curr = next
+	return st.ScanWith(ctx, s, MatchOptions{}, r)
+}
+
+// ScanWith is same as ScanContext, but reports only matches which are
+// specified by opts.
+func (st *STree) ScanWith(ctx context.Context, s string, opts MatchOptions, r ScanReporter) error {
+	return scanContext[int](ctx, st.indexed(), s, r, opts)
+}
+
+// ScanBytes scans a byte slice to find matched words.
+func (st *STree) ScanBytes(b []byte, r ScanReporter) error {
+	return scanContext[int](context.Background(), st.indexed(), b, r, MatchOptions{})
+}
+
+// normalize applies Normalizer to a rune.
+func (st *STree) normalize(r rune) rune {
+	if st.Normalizer == nil {
+		return r
 	}
-	return nil
+	return st.Normalizer(r)
 }
 
 func (st *STree) nextNode(x int, c rune) int {
+	if st.dfa != nil {
+		return st.dfa.nextNode(x, c)
+	}
 	for {
 		n := &st.Nodes[x]
 		next := st.find(n.Start, n.End, c)
@@ -131,44 +282,109 @@ func (st *STree) find(a, b int, c rune) int {
 	return -1
 }
 
+// Get finds an edge for key k, and returns its ID.
+// ok will be false when k is not a key of the tree.
+func (st *STree) Get(k string) (edgeID int, ok bool) {
+	x := st.getNode(k)
+	if x < 0 {
+		return 0, false
+	}
+	edgeID = st.Nodes[x].EdgeID
+	return edgeID, edgeID > 0
+}
+
+// Key returns a key string for the edge ID.
+// ok will be false when no edges have the ID.
+func (st *STree) Key(id int) (k string, ok bool) {
+	parents, edges := st.keyIndex()
+	if id <= 0 || id > len(edges) {
+		return "", false
+	}
+	x := edges[id-1]
+	if x < 0 {
+		return "", false
+	}
+	var rs []rune
+	for ; x > 0; x = parents[x] {
+		rs = append(rs, st.Nodes[x].Label)
+	}
+	var b []byte
+	for _, r := range slices.Backward(rs) {
+		b = appendLabel(b, r, st.ByteLabels)
+	}
+	return string(b), true
+}
+
+// getNode retrieves an index of node for key, otherwise returns -1.
+func (st *STree) getNode(k string) int {
+	if len(st.Nodes) == 0 {
+		return -1
+	}
+	x := 0
+	for i := 0; i < len(k); {
+		r, sz := decodeLabel(k[i:], st.ByteLabels)
+		n := &st.Nodes[x]
+		x = st.find(n.Start, n.End, st.normalize(r))
+		if x < 0 {
+			return -1
+		}
+		i += sz
+	}
+	return x
+}
+
 // LongestPrefix finds a longest prefix node/edge matches given s string.
 func (st *STree) LongestPrefix(s string) (prefix string, edgeID int) {
 	last := -1
-	ilast := 0
+	end := 0
 	curr := 0
-	for i, r := range s {
+	for i := 0; i < len(s); {
+		r, sz := decodeLabel(s[i:], st.ByteLabels)
 		n := st.Nodes[curr]
-		next := st.find(n.Start, n.End, r)
+		next := st.find(n.Start, n.End, st.normalize(r))
 		if next < 0 {
 			break
 		}
+		i += sz
 		if st.Nodes[next].EdgeID > 0 {
 			last = next
-			ilast = i
+			end = i
 		}
 		curr = next
 	}
 	if last < 0 {
 		return "", 0
 	}
-	n := st.Nodes[last]
-	return s[:ilast+utf8.RuneLen(n.Label)], n.EdgeID
+	return s[:end], st.Nodes[last].EdgeID
 }
 
 // Write serializes a tree to io.Writer.
 func (st *STree) Write(w io.Writer) error {
 	ww := newWriter(w)
 
+	// write header.
+	flags := flagOutputs
+	if st.ByteLabels {
+		flags |= flagByteLabels
+	}
+	ww.writeInt(-formatVersion)
+	ww.writeInt(flags)
+
 	// write nodes.
 	ww.writeInt(len(st.Nodes))
 	if ww.err != nil {
 		return ww.err
 	}
-	for _, n := range st.Nodes {
+	outputs := st.outputs
+	if outputs == nil {
+		outputs = st.buildOutputs()
+	}
+	for i, n := range st.Nodes {
 		err := n.write(ww)
 		if err != nil {
 			return err
 		}
+		ww.writeInt(outputs[i])
 	}
 
 	// write levels.
@@ -189,25 +405,63 @@ func (st *STree) Write(w io.Writer) error {
 
 const intSize = 32 << (^uint(0) >> 63)
 
+// formatVersion is the version of serialized format.  Version 1 has no
+// header, it starts with the number of nodes.  Later versions start with a
+// negated version number as a header, and flags follow it.
+const formatVersion = 2
+
+// flags in the header of serialized format.
+const (
+	flagByteLabels = 1 << iota
+	flagOutputs
+
+	knownFlags = flagByteLabels | flagOutputs
+)
+
 // Read reads static tree from io.Reader.
 func Read(r io.Reader) (*STree, error) {
 	rr := newReader(r)
 
-	// read nodes.
+	// read header.
 	n, err := rr.readInt64()
 	if err != nil {
 		return nil, err
 	}
+	var flags int64
+	if n < 0 {
+		if -n != formatVersion {
+			return nil, fmt.Errorf("unsupported format version: %d", -n)
+		}
+		flags, err = rr.readInt64()
+		if err != nil {
+			return nil, err
+		}
+		if flags&^knownFlags != 0 {
+			return nil, fmt.Errorf("unknown format flags: %#x", flags&^knownFlags)
+		}
+		// read nodes.
+		n, err = rr.readInt64()
+		if err != nil {
+			return nil, err
+		}
+	}
 	// check 32 bit overflow.
 	if intSize == 32 && n > math.MaxInt32 {
 		return nil, errors.New("too large tree for 32bit architecture")
 	}
 	nodes := make([]SNode, int(n))
+	var outputs []int
+	if flags&flagOutputs != 0 {
+		outputs = make([]int, len(nodes))
+	}
 	for i := range nodes {
 		err := nodes[i].read(rr)
 		if err != nil {
 			return nil, err
 		}
+		if outputs != nil {
+			outputs[i] = rr.readInt()
+		}
 	}
 
 	// read levels.
@@ -226,10 +480,14 @@ func Read(r io.Reader) (*STree, error) {
 		return nil, rr.err
 	}
 
-	return &STree{
-		Nodes:  nodes,
-		Levels: levels,
-	}, nil
+	st := &STree{
+		Nodes:      nodes,
+		Levels:     levels,
+		ByteLabels: flags&flagByteLabels != 0,
+	}
+	st.outputs = outputs
+	st.buildIndex()
+	return st, nil
 }
 
 // SNode is a node for static tree.
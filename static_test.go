@@ -120,3 +120,75 @@ func TestSTree_ScanMultiple(t *testing.T) {
 		{2, 'd', []node{{4, 1}}},
 	})
 }
+
+func TestSTree_Get(t *testing.T) {
+	dt := testDTreePut(t, &trietree.DTree{}, "ab", "bc", "bab", "d", "abcde")
+	st := trietree.Freeze(dt)
+	for i, c := range []struct {
+		key    string
+		wantID int
+		wantOK bool
+	}{
+		{"ab", 1, true},
+		{"bc", 2, true},
+		{"bab", 3, true},
+		{"d", 4, true},
+		{"abcde", 5, true},
+		{"", 0, false},
+		{"a", 0, false},
+		{"abc", 0, false},
+		{"abcdef", 0, false},
+		{"zzz", 0, false},
+	} {
+		gotID, gotOK := st.Get(c.key)
+		if gotID != c.wantID || gotOK != c.wantOK {
+			t.Errorf("unexpected #%d %q: want=(%d, %t) got=(%d, %t)", i, c.key, c.wantID, c.wantOK, gotID, gotOK)
+		}
+	}
+}
+
+func TestSTree_Key(t *testing.T) {
+	keys := []string{"ab", "bc", "bab", "d", "abcde", "あいう"}
+	dt := testDTreePut(t, &trietree.DTree{}, keys...)
+	st0 := trietree.Freeze(dt)
+	b := &bytes.Buffer{}
+	if err := st0.Write(b); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	st1, err := trietree.Read(b)
+	if err != nil {
+		t.Fatalf("read failed: %s", err)
+	}
+	// a tree assembled by hand builds indexes for each call.
+	st2 := &trietree.STree{Nodes: st0.Nodes, Levels: st0.Levels}
+	// STree is a plain value, which can be copied.
+	st3 := *st0
+	for _, st := range []*trietree.STree{st0, st1, st2, &st3} {
+		for i, want := range keys {
+			got, ok := st.Key(i + 1)
+			if !ok {
+				t.Errorf("key for ID=%d not found", i+1)
+				continue
+			}
+			if got != want {
+				t.Errorf("unexpected key for ID=%d: want=%q got=%q", i+1, want, got)
+			}
+		}
+		for _, id := range []int{-1, 0, len(keys) + 1} {
+			if got, ok := st.Key(id); ok {
+				t.Errorf("unexpected key for ID=%d: %q", id, got)
+			}
+		}
+	}
+}
+
+func TestSTree_deepFailure(t *testing.T) {
+	dt := testDTreePut(t, &trietree.DTree{}, "abcd", "bc", "cd")
+	st := trietree.Freeze(dt)
+	testSTreeScan(t, st, "abcd", reports{
+		{0, 'a', nil},
+		{1, 'b', nil},
+		{2, 'c', []node{{2, 2}}},
+		{3, 'd', []node{{1, 4}, {3, 2}}},
+	})
+}
@@ -53,7 +53,7 @@ func TestTrie_Basic(t *testing.T) {
 		{Index: 1, Label: 'a'},
 		{Index: 2, Label: 'b', Nodes: []ReportNode{
 			{ID: 3, Level: 3},
-			//{ID: 1, Level: 2},
+			{ID: 1, Level: 2},
 		}},
 	})
 	testScan(t, tr, "d", []ReportEvent{
@@ -64,8 +64,8 @@ func TestTrie_Basic(t *testing.T) {
 	testScan(t, tr, "abcde", []ReportEvent{
 		{Index: 0, Label: 'a'},
 		{Index: 1, Label: 'b', Nodes: []ReportNode{{ID: 1, Level: 2}}},
-		{Index: 2, Label: 'c'},
-		{Index: 3, Label: 'd'},
+		{Index: 2, Label: 'c', Nodes: []ReportNode{{ID: 2, Level: 2}}},
+		{Index: 3, Label: 'd', Nodes: []ReportNode{{ID: 4, Level: 1}}},
 		{Index: 4, Label: 'e', Nodes: []ReportNode{{ID: 5, Level: 5}}},
 	})
 }
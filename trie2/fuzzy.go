@@ -0,0 +1,42 @@
+package trie2
+
+import (
+	"iter"
+
+	"github.com/koron-go/trietree"
+)
+
+// FuzzyMatch is a pair of key and value which found by FuzzySearch.
+type FuzzyMatch[T any] struct {
+	Key      string // Key is the found key.
+	Value    T      // Value is the value corresponding to the key.
+	Distance int    // Distance is Levenshtein distance between Key and query.
+}
+
+func fuzzyMatches[T any](iter iter.Seq[trietree.FuzzyMatch], values []T) iter.Seq[FuzzyMatch[T]] {
+	return func(yield func(FuzzyMatch[T]) bool) {
+		for m := range iter {
+			if !yield(FuzzyMatch[T]{
+				Key:      m.Key,
+				Value:    values[m.ID-1],
+				Distance: m.Distance,
+			}) {
+				return
+			}
+		}
+	}
+}
+
+// FuzzySearch returns an iterator which enumerates pairs of key and value,
+// which key is within Levenshtein distance k from q.  Keys are enumerated in
+// lexicographic order.
+func (dt *DTrie[T]) FuzzySearch(q string, k int) iter.Seq[FuzzyMatch[T]] {
+	return fuzzyMatches(dt.tree.FuzzySearch(q, k), dt.values)
+}
+
+// FuzzySearch returns an iterator which enumerates pairs of key and value,
+// which key is within Levenshtein distance k from q.  Keys are enumerated in
+// lexicographic order.
+func (st *STrie[T]) FuzzySearch(q string, k int) iter.Seq[FuzzyMatch[T]] {
+	return fuzzyMatches(st.tree.FuzzySearch(q, k), st.values)
+}
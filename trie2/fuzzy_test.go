@@ -0,0 +1,43 @@
+package trie2
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fuzzySearcher[T any] interface {
+	FuzzySearch(q string, k int) iter.Seq[FuzzyMatch[T]]
+}
+
+func TestFuzzySearch(t *testing.T) {
+	dt, st := testTries(t)
+	for i, c := range []struct {
+		q    string
+		k    int
+		want []FuzzyMatch[Data]
+	}{
+		{"ac", 1, []FuzzyMatch[Data]{
+			{Key: "a", Value: Data{111, "aaa"}, Distance: 1},
+			{Key: "ab", Value: Data{222, "bbb"}, Distance: 1},
+			{Key: "abc", Value: Data{333, "ccc"}, Distance: 1},
+		}},
+		{"dd", 1, []FuzzyMatch[Data]{
+			{Key: "d", Value: Data{444, "ddd"}, Distance: 1},
+			{Key: "de", Value: Data{555, "eee"}, Distance: 1},
+		}},
+		{"zzz", 1, nil},
+	} {
+		for _, fs := range []fuzzySearcher[Data]{dt, st} {
+			t.Run(fmt.Sprintf("%T i:%d q:%s", fs, i, c.q), func(t *testing.T) {
+				got := slices.Collect(fs.FuzzySearch(c.q, c.k))
+				if d := cmp.Diff(c.want, got); d != "" {
+					t.Errorf("unexpected matches: -want +got\n%s", d)
+				}
+			})
+		}
+	}
+}
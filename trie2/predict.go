@@ -39,6 +39,18 @@ func (st *STrie[T]) PredictIter(query string) PredictionIter[T] {
 	return predictIter(query, st.tree.PredictIter(query), st.values)
 }
 
+// PredictIterWith is same as PredictIter, but reports only matches which are
+// specified by opts.
+func (dt *DTrie[T]) PredictIterWith(query string, opts trietree.MatchOptions) PredictionIter[T] {
+	return predictIter(query, dt.tree.PredictIterWith(query, opts), dt.values)
+}
+
+// PredictIterWith is same as PredictIter, but reports only matches which are
+// specified by opts.
+func (st *STrie[T]) PredictIterWith(query string, opts trietree.MatchOptions) PredictionIter[T] {
+	return predictIter(query, st.tree.PredictIterWith(query, opts), st.values)
+}
+
 func predict[T any](query string, iter iter.Seq[trietree.Prediction], values []T) iter.Seq[Prediction[T]] {
 	return func(yield func(Prediction[T]) bool) {
 		for p := range iter {
@@ -63,3 +75,15 @@ func (dt *DTrie[T]) Predict(query string) iter.Seq[Prediction[T]] {
 func (st *STrie[T]) Predict(query string) iter.Seq[Prediction[T]] {
 	return predict[T](query, st.tree.Predict(query), st.values)
 }
+
+// PredictWith returns an iterator which enumerates Prediction, which are
+// specified by opts.
+func (dt *DTrie[T]) PredictWith(query string, opts trietree.MatchOptions) iter.Seq[Prediction[T]] {
+	return predict[T](query, dt.tree.PredictWith(query, opts), dt.values)
+}
+
+// PredictWith returns an iterator which enumerates Prediction, which are
+// specified by opts.
+func (st *STrie[T]) PredictWith(query string, opts trietree.MatchOptions) iter.Seq[Prediction[T]] {
+	return predict[T](query, st.tree.PredictWith(query, opts), st.values)
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
 )
 
 type predictIterator[T any] interface {
@@ -94,3 +95,40 @@ func TestPredict(t *testing.T) {
 		})
 	}
 }
+
+func TestPredictWithoutFillFailure(t *testing.T) {
+	dt := &DTrie[Data]{}
+	dt.Put("ab", Data{111, "aaa"})
+	dt.Put("bab", Data{222, "bbb"})
+	testPredict(t, dt, "bab", []Prediction[Data]{
+		{Start: 0, End: 3, Key: "bab", Value: Data{222, "bbb"}},
+		{Start: 1, End: 3, Key: "ab", Value: Data{111, "aaa"}},
+	})
+	dt.Put("b", Data{333, "ccc"})
+	testPredict(t, dt, "bab", []Prediction[Data]{
+		{Start: 0, End: 1, Key: "b", Value: Data{333, "ccc"}},
+		{Start: 0, End: 3, Key: "bab", Value: Data{222, "bbb"}},
+		{Start: 1, End: 3, Key: "ab", Value: Data{111, "aaa"}},
+		{Start: 2, End: 3, Key: "b", Value: Data{333, "ccc"}},
+	})
+}
+
+func TestPredictLeftmostLongest(t *testing.T) {
+	dt := &DTrie[Data]{}
+	dt.Put("ab", Data{111, "aaa"})
+	dt.Put("bab", Data{222, "bbb"})
+	dt.Put("b", Data{333, "ccc"})
+	want := []Prediction[Data]{
+		{Start: 0, End: 3, Key: "bab", Value: Data{222, "bbb"}},
+		{Start: 3, End: 5, Key: "ab", Value: Data{111, "aaa"}},
+	}
+	opts := trietree.MatchOptions{Kind: trietree.LeftmostLongest}
+	for _, tr := range []interface {
+		PredictWith(string, trietree.MatchOptions) iter.Seq[Prediction[Data]]
+	}{dt, dt.Freeze(false)} {
+		got := slices.Collect(tr.PredictWith("babab", opts))
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("unexpected predictions for %T: -want +got\n%s", tr, d)
+		}
+	}
+}
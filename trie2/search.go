@@ -0,0 +1,66 @@
+package trie2
+
+import (
+	"iter"
+	"regexp/syntax"
+)
+
+// CommonPrefixes returns an iterator which enumerates Prediction for all keys
+// which are prefixes of s, from shorter to longer.
+func (dt *DTrie[T]) CommonPrefixes(s string) iter.Seq[Prediction[T]] {
+	return predict[T](s, dt.tree.CommonPrefixes(s), dt.values)
+}
+
+// CommonPrefixes returns an iterator which enumerates Prediction for all keys
+// which are prefixes of s, from shorter to longer.
+func (st *STrie[T]) CommonPrefixes(s string) iter.Seq[Prediction[T]] {
+	return predict[T](s, st.tree.CommonPrefixes(s), st.values)
+}
+
+func keyValues[T any](iter iter.Seq2[string, int], values []T) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for k, id := range iter {
+			if !yield(k, values[id-1]) {
+				return
+			}
+		}
+	}
+}
+
+// PrefixSearch returns an iterator which enumerates all keys which start with
+// prefix, and their values.  Keys are enumerated in lexicographic order.
+func (dt *DTrie[T]) PrefixSearch(prefix string) iter.Seq2[string, T] {
+	return keyValues(dt.tree.PrefixSearch(prefix), dt.values)
+}
+
+// PrefixSearch returns an iterator which enumerates all keys which start with
+// prefix, and their values.  Keys are enumerated in lexicographic order.
+func (st *STrie[T]) PrefixSearch(prefix string) iter.Seq2[string, T] {
+	return keyValues(st.tree.PrefixSearch(prefix), st.values)
+}
+
+// All returns an iterator which enumerates all keys in the trie-tree, and
+// their values.  Keys are enumerated in lexicographic order.
+func (dt *DTrie[T]) All() iter.Seq2[string, T] {
+	return keyValues(dt.tree.All(), dt.values)
+}
+
+// All returns an iterator which enumerates all keys in the trie-tree, and
+// their values.  Keys are enumerated in lexicographic order.
+func (st *STrie[T]) All() iter.Seq2[string, T] {
+	return keyValues(st.tree.All(), st.values)
+}
+
+// MatchRegexp returns an iterator which enumerates keys which match with a
+// compiled regular expression program, and their values.  See
+// trietree.STree.MatchRegexp for details.
+func (dt *DTrie[T]) MatchRegexp(prog *syntax.Prog) iter.Seq2[string, T] {
+	return keyValues(dt.tree.MatchRegexp(prog), dt.values)
+}
+
+// MatchRegexp returns an iterator which enumerates keys which match with a
+// compiled regular expression program, and their values.  See
+// trietree.STree.MatchRegexp for details.
+func (st *STrie[T]) MatchRegexp(prog *syntax.Prog) iter.Seq2[string, T] {
+	return keyValues(st.tree.MatchRegexp(prog), st.values)
+}
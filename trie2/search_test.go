@@ -0,0 +1,157 @@
+package trie2
+
+import (
+	"fmt"
+	"iter"
+	"regexp/syntax"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testTries(t *testing.T) (*DTrie[Data], *STrie[Data]) {
+	t.Helper()
+	dt := &DTrie[Data]{}
+	dt.Put("a", Data{111, "aaa"})
+	dt.Put("ab", Data{222, "bbb"})
+	dt.Put("abc", Data{333, "ccc"})
+	dt.Put("d", Data{444, "ddd"})
+	dt.Put("de", Data{555, "eee"})
+	return dt, dt.Freeze(false)
+}
+
+type commonPrefixer[T any] interface {
+	CommonPrefixes(string) iter.Seq[Prediction[T]]
+}
+
+func TestCommonPrefixes(t *testing.T) {
+	dt, st := testTries(t)
+	for i, c := range []struct {
+		q    string
+		want []Prediction[Data]
+	}{
+		{"abcd", []Prediction[Data]{
+			{Start: 0, End: 1, Key: "a", Value: Data{111, "aaa"}},
+			{Start: 0, End: 2, Key: "ab", Value: Data{222, "bbb"}},
+			{Start: 0, End: 3, Key: "abc", Value: Data{333, "ccc"}},
+		}},
+		{"dz", []Prediction[Data]{
+			{Start: 0, End: 1, Key: "d", Value: Data{444, "ddd"}},
+		}},
+		{"zd", nil},
+	} {
+		for _, cp := range []commonPrefixer[Data]{dt, st} {
+			t.Run(fmt.Sprintf("%T i:%d q:%s", cp, i, c.q), func(t *testing.T) {
+				got := slices.Collect(cp.CommonPrefixes(c.q))
+				if d := cmp.Diff(c.want, got); d != "" {
+					t.Errorf("unexpected predictions: -want +got\n%s", d)
+				}
+			})
+		}
+	}
+}
+
+type keyValue[T any] struct {
+	Key   string
+	Value T
+}
+
+func collectKeyValues[T any](seq iter.Seq2[string, T]) []keyValue[T] {
+	var got []keyValue[T]
+	for k, v := range seq {
+		got = append(got, keyValue[T]{Key: k, Value: v})
+	}
+	return got
+}
+
+type prefixSearcher[T any] interface {
+	PrefixSearch(string) iter.Seq2[string, T]
+}
+
+func TestPrefixSearch(t *testing.T) {
+	dt, st := testTries(t)
+	for i, c := range []struct {
+		prefix string
+		want   []keyValue[Data]
+	}{
+		{"ab", []keyValue[Data]{
+			{"ab", Data{222, "bbb"}},
+			{"abc", Data{333, "ccc"}},
+		}},
+		{"d", []keyValue[Data]{
+			{"d", Data{444, "ddd"}},
+			{"de", Data{555, "eee"}},
+		}},
+		{"z", nil},
+	} {
+		for _, ps := range []prefixSearcher[Data]{dt, st} {
+			t.Run(fmt.Sprintf("%T i:%d prefix:%s", ps, i, c.prefix), func(t *testing.T) {
+				got := collectKeyValues(ps.PrefixSearch(c.prefix))
+				if d := cmp.Diff(c.want, got); d != "" {
+					t.Errorf("unexpected key-values: -want +got\n%s", d)
+				}
+			})
+		}
+	}
+}
+
+type allIterator[T any] interface {
+	All() iter.Seq2[string, T]
+}
+
+func TestAll(t *testing.T) {
+	dt, st := testTries(t)
+	want := []keyValue[Data]{
+		{"a", Data{111, "aaa"}},
+		{"ab", Data{222, "bbb"}},
+		{"abc", Data{333, "ccc"}},
+		{"d", Data{444, "ddd"}},
+		{"de", Data{555, "eee"}},
+	}
+	for _, tr := range []allIterator[Data]{dt, st} {
+		got := collectKeyValues(tr.All())
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("unexpected key-values %T: -want +got\n%s", tr, d)
+		}
+	}
+}
+
+type regexpMatcher[T any] interface {
+	MatchRegexp(*syntax.Prog) iter.Seq2[string, T]
+}
+
+func TestMatchRegexp(t *testing.T) {
+	dt, st := testTries(t)
+	for i, c := range []struct {
+		expr string
+		want []keyValue[Data]
+	}{
+		{`^ab?$`, []keyValue[Data]{
+			{"a", Data{111, "aaa"}},
+			{"ab", Data{222, "bbb"}},
+		}},
+		{`[ce]$`, []keyValue[Data]{
+			{"abc", Data{333, "ccc"}},
+			{"de", Data{555, "eee"}},
+		}},
+		{`z`, nil},
+	} {
+		re, err := syntax.Parse(c.expr, syntax.Perl)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %s", c.expr, err)
+		}
+		prog, err := syntax.Compile(re.Simplify())
+		if err != nil {
+			t.Fatalf("failed to compile %q: %s", c.expr, err)
+		}
+		for _, rm := range []regexpMatcher[Data]{dt, st} {
+			t.Run(fmt.Sprintf("%T i:%d expr:%s", rm, i, c.expr), func(t *testing.T) {
+				got := collectKeyValues(rm.MatchRegexp(prog))
+				if d := cmp.Diff(c.want, got); d != "" {
+					t.Errorf("unexpected key-values: -want +got\n%s", d)
+				}
+			})
+		}
+	}
+}
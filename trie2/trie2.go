@@ -15,6 +15,8 @@ type DTrie[T any] struct {
 }
 
 // FillFailure fill Failure field with Aho-Corasick algorithm.
+// Predict and PredictIter fill Failure field automatically when the trie was
+// modified, but call this explicitly before predicting concurrently.
 func (dt *DTrie[T]) FillFailure() {
 	dt.tree.FillFailure()
 }
@@ -30,6 +32,32 @@ func (dt *DTrie[T]) Put(k string, v T) {
 	dt.values[id-1] = v
 }
 
+// Delete removes a pair of key and value.  It returns the removed value.  ok
+// will be false when k is not found in the trie-tree.
+func (dt *DTrie[T]) Delete(k string) (v T, ok bool) {
+	id, ok := dt.tree.Delete(k)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	// clear the value slot, keep the slot itself to retain IDs of others.
+	v = dt.values[id-1]
+	var zero T
+	dt.values[id-1] = zero
+	return v, true
+}
+
+// Get returns a value corresponding to key k.  ok will be false when k is not
+// found in the trie-tree.
+func (dt *DTrie[T]) Get(k string) (v T, ok bool) {
+	n := dt.tree.Get(k)
+	if n == nil || n.EdgeID <= 0 {
+		var zero T
+		return zero, false
+	}
+	return dt.values[n.EdgeID-1], true
+}
+
 // LongestPrefix performs "logest prefix match" with s.  It will return a
 // corresponding value and prefix when s found in the trie-tree.
 func (dt *DTrie[T]) LongestPrefix(s string) (v T, prefix string, ok bool) {
@@ -123,3 +151,41 @@ func (st *STrie[T]) LongestPrefix(s string) (v T, prefix string, ok bool) {
 	}
 	return st.values[id-1], prefix, true
 }
+
+// Get returns a value corresponding to key k.  ok will be false when k is not
+// found in the trie-tree.
+func (st *STrie[T]) Get(k string) (v T, ok bool) {
+	id, ok := st.tree.Get(k)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return st.values[id-1], true
+}
+
+// SetNormalizer sets a normalizer which is applied to keys and queries.  It
+// should be set before putting any pairs.
+func (dt *DTrie[T]) SetNormalizer(n trietree.Normalizer) {
+	dt.tree.Normalizer = n
+}
+
+// SetNormalizer sets a normalizer which is applied to queries.  Freeze copies
+// the normalizer of DTrie, but Unmarshal doesn't.  So set the same one after
+// Unmarshal.
+func (st *STrie[T]) SetNormalizer(n trietree.Normalizer) {
+	st.tree.Normalizer = n
+}
+
+// SetByteLabels makes the trie treat each byte of keys and queries as a label,
+// instead of each rune.  It should be set before putting any pairs.  Freeze
+// and Marshal keep this setting.
+func (dt *DTrie[T]) SetByteLabels(v bool) {
+	dt.tree.ByteLabels = v
+}
+
+// CompileDFA precomputes transitions of the automaton to speed up Predict and
+// PredictIter.  It returns false when the table exceeds limit bytes.  See
+// trietree.STree.CompileDFA for details.
+func (st *STrie[T]) CompileDFA(limit int) bool {
+	return st.tree.CompileDFA(limit)
+}
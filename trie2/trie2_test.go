@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"testing"
+	"unicode"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -167,3 +168,103 @@ func TestLongestPrefixSTree(t *testing.T) {
 		}
 	}
 }
+
+func TestGet(t *testing.T) {
+	dt := &DTrie[Data]{}
+	dt.Put("a", Data{111, "aaa"})
+	dt.Put("ab", Data{222, "bbb"})
+	dt.Put("abc", Data{333, "ccc"})
+	dt.Put("d", Data{444, "ddd"})
+	dt.Put("de", Data{555, "eee"})
+	st := dt.Freeze(false)
+	type getter interface {
+		Get(string) (Data, bool)
+	}
+	for i, c := range []struct {
+		key   string
+		wantV Data
+		wantF bool
+	}{
+		{"a", Data{111, "aaa"}, true},
+		{"abc", Data{333, "ccc"}, true},
+		{"de", Data{555, "eee"}, true},
+		{"abcd", Data{}, false},
+		{"e", Data{}, false},
+		{"", Data{}, false},
+	} {
+		for _, g := range []getter{dt, st} {
+			gotV, gotF := g.Get(c.key)
+			if gotF != c.wantF {
+				t.Errorf("existence unmatch #%d %T: want=%t got=%t", i, g, c.wantF, gotF)
+				continue
+			}
+			if d := cmp.Diff(c.wantV, gotV); d != "" {
+				t.Errorf("values unmatch #%d %T: -want +got\n%s", i, g, d)
+			}
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	dt, _ := testTries(t)
+	v, ok := dt.Delete("ab")
+	if !ok {
+		t.Fatal("failed to delete \"ab\"")
+	}
+	if d := cmp.Diff(Data{222, "bbb"}, v); d != "" {
+		t.Errorf("unexpected deleted value: -want +got\n%s", d)
+	}
+	if _, ok := dt.Delete("ab"); ok {
+		t.Error("\"ab\" is deleted twice")
+	}
+	if _, ok := dt.Get("ab"); ok {
+		t.Error("deleted \"ab\" is found")
+	}
+	if v, ok := dt.Get("abc"); !ok || v != (Data{333, "ccc"}) {
+		t.Errorf("unexpected value for \"abc\": %+v %t", v, ok)
+	}
+	if d := cmp.Diff(Data{}, dt.values[1]); d != "" {
+		t.Errorf("value slot is not cleared: -want +got\n%s", d)
+	}
+
+	// deleted trie can be marshaled.
+	bb := &bytes.Buffer{}
+	if err := dt.Freeze(false).Marshal(bb, nil); err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	st, err := Unmarshal[Data](bb, nil)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+	want := []keyValue[Data]{
+		{"a", Data{111, "aaa"}},
+		{"abc", Data{333, "ccc"}},
+		{"d", Data{444, "ddd"}},
+		{"de", Data{555, "eee"}},
+	}
+	if d := cmp.Diff(want, collectKeyValues(st.All())); d != "" {
+		t.Errorf("unexpected key-values: -want +got\n%s", d)
+	}
+}
+
+func TestNormalizer(t *testing.T) {
+	dt := &DTrie[Data]{}
+	dt.SetNormalizer(unicode.ToLower)
+	dt.Put("Foo", Data{111, "aaa"})
+	dt.Put("BAR", Data{222, "bbb"})
+	bb := &bytes.Buffer{}
+	if err := dt.Freeze(false).Marshal(bb, nil); err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	st, err := Unmarshal[Data](bb, nil)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+	st.SetNormalizer(unicode.ToLower)
+	for _, ptor := range []predictor[Data]{dt, dt.Freeze(false), st} {
+		testPredict(t, ptor, "fOO Bar", []Prediction[Data]{
+			{Start: 0, End: 3, Key: "fOO", Value: Data{111, "aaa"}},
+			{Start: 4, End: 7, Key: "Bar", Value: Data{222, "bbb"}},
+		})
+	}
+}
@@ -1,8 +1,17 @@
 /*
-Package trietree provides two trie-tree (prefix tree) implementations.
+Package trietree provides trie-tree (prefix tree) implementations: DTree is
+dynamic, STree is static and compact, DATree is static in double-array form
+for fast lookups, and LTree is static in LOUDS form for large dictionaries.
 */
 package trietree
 
+import "context"
+
+// Normalizer maps a rune to another rune, for example case folding.  It is
+// applied to runes of keys and queries before walking a tree.  Indexes which
+// reported by trees always point to the original query.
+type Normalizer func(r rune) rune
+
 // ScanEvent is an event which detected in Scan/ScanContext.
 type ScanEvent struct {
 	Index int
@@ -37,11 +46,8 @@ type scanReport struct {
 	nodesCurr []ScanNode
 }
 
-func newScanReport(r ScanReporter, n int) *scanReport {
-	return &scanReport{
-		r:        r,
-		nodesBuf: make([]ScanNode, n),
-	}
+func newScanReport(r ScanReporter) *scanReport {
+	return &scanReport{r: r}
 }
 
 func (sr *scanReport) reset(i int, c rune) {
@@ -61,4 +67,63 @@ func (sr *scanReport) emit() {
 	}
 	sr.ev.Nodes = nodes
 	sr.r.ScanReport(sr.ev)
+	// keep the buffer which may be grown by add.
+	sr.nodesBuf = sr.nodesCurr[:0]
+}
+
+// scanContext scans s with tree, and reports to r for each labels.
+func scanContext[T comparable, S byteSeq](ctx context.Context, tree predictableTree[T], s S, r ScanReporter, opts MatchOptions) error {
+	if opts.Kind != Standard {
+		return scanLeftmost(ctx, tree, s, r, opts)
+	}
+	var zero T
+	sr := newScanReport(r)
+	byteLabels := tree.byteLabels()
+	isWord := opts.WordRune
+	curr := tree.root()
+	for i := 0; i < len(s); {
+		c, sz := decodeLabel(s[i:], byteLabels)
+		next := tree.nextNode(curr, tree.normalize(c))
+		// emit a scan event.
+		sr.reset(i, c)
+		for n := matchedNode(tree, next); n != zero; n = tree.nodeOutput(n) {
+			lv := tree.nodeLevel(n)
+			if isWord != nil && !isBoundary(s, trailingIndex(s[:i+sz], lv, byteLabels), i+sz, byteLabels, isWord) {
+				continue
+			}
+			sr.add(tree.nodeId(n), lv)
+		}
+		sr.emit()
+		// prepare for next.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		curr = next
+		i += sz
+	}
+	return nil
+}
+
+// scanLeftmost scans s with tree, and reports to r for each labels.  Only
+// non-overlapping matches which are found by leftmostFinder are reported.
+func scanLeftmost[T comparable, S byteSeq](ctx context.Context, tree predictableTree[T], s S, r ScanReporter, opts MatchOptions) error {
+	sr := newScanReport(r)
+	byteLabels := tree.byteLabels()
+	lf := newLeftmostFinder(tree, s, opts)
+	p, node, ok := lf.next()
+	for i := 0; i < len(s); {
+		c, sz := decodeLabel(s[i:], byteLabels)
+		i += sz
+		// emit a scan event.
+		sr.reset(i-sz, c)
+		if ok && p.End == i {
+			sr.add(p.ID, tree.nodeLevel(node))
+			p, node, ok = lf.next()
+		}
+		sr.emit()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
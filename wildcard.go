@@ -0,0 +1,132 @@
+package trietree
+
+import (
+	"iter"
+	"slices"
+)
+
+// Match returns an iterator which enumerates keys which match with a wildcard
+// pattern, and their edge IDs.  In the pattern, '?' matches any one rune, and
+// '*' matches any sequence of runes including empty.  Keys are enumerated in
+// lexicographic order.
+func (dt *DTree) Match(pattern string) iter.Seq2[string, int] {
+	return matchWildcard[*DNode](dt, pattern)
+}
+
+// Match returns an iterator which enumerates keys which match with a wildcard
+// pattern, and their edge IDs.  In the pattern, '?' matches any one rune, and
+// '*' matches any sequence of runes including empty.  Keys are enumerated in
+// lexicographic order.
+func (st *STree) Match(pattern string) iter.Seq2[string, int] {
+	return matchWildcard[int](st, pattern)
+}
+
+func matchWildcard[T comparable](tree searchableTree[T], pattern string) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		ww := wildcardWalker[T]{
+			tree:  tree,
+			pat:   decodeLabels(pattern, tree.byteLabels()),
+			yield: yield,
+		}
+		for i, r := range ww.pat {
+			if r != '?' && r != '*' {
+				ww.pat[i] = tree.normalize(r)
+			}
+		}
+		ww.walk(tree.root(), make([]byte, 0, 16), ww.add(nil, 0))
+	}
+}
+
+// wildcardWalker walks a tree with states of a wildcard pattern.  A state is
+// a position in the pattern.
+type wildcardWalker[T comparable] struct {
+	tree  searchableTree[T]
+	pat   []rune
+	yield func(string, int) bool
+}
+
+// add adds a state p to states, with states which can be reached from p
+// without consuming any runes.
+func (ww *wildcardWalker[T]) add(states []int, p int) []int {
+	if slices.Contains(states, p) {
+		return states
+	}
+	states = append(states, p)
+	if p < len(ww.pat) && ww.pat[p] == '*' {
+		states = ww.add(states, p+1)
+	}
+	return states
+}
+
+// step returns next states after consuming a rune r.
+func (ww *wildcardWalker[T]) step(states []int, r rune) []int {
+	var next []int
+	for _, p := range states {
+		if p >= len(ww.pat) {
+			continue
+		}
+		switch c := ww.pat[p]; c {
+		case '*':
+			next = ww.add(next, p)
+		case '?':
+			next = ww.add(next, p+1)
+		default:
+			if c == r {
+				next = ww.add(next, p+1)
+			}
+		}
+	}
+	return next
+}
+
+// literals returns runes which are acceptable by states in order, or false
+// when states accept any runes.
+func (ww *wildcardWalker[T]) literals(states []int) ([]rune, bool) {
+	var labels []rune
+	for _, p := range states {
+		if p >= len(ww.pat) {
+			continue
+		}
+		switch c := ww.pat[p]; c {
+		case '*', '?':
+			return nil, false
+		default:
+			labels = append(labels, c)
+		}
+	}
+	slices.Sort(labels)
+	return slices.Compact(labels), true
+}
+
+// walk visits the node and its descendants with states.  This returns false
+// when the search should stop.
+func (ww *wildcardWalker[T]) walk(node T, buf []byte, states []int) bool {
+	if id := ww.tree.nodeId(node); id > 0 && slices.Contains(states, len(ww.pat)) {
+		if !ww.yield(string(buf), id) {
+			return false
+		}
+	}
+	// visit only children which have acceptable labels, when possible.
+	if labels, ok := ww.literals(states); ok {
+		for _, r := range labels {
+			child, ok := ww.tree.child(node, r)
+			if !ok {
+				continue
+			}
+			if !ww.walk(child, appendLabel(buf, r, ww.tree.byteLabels()), ww.step(states, r)) {
+				return false
+			}
+		}
+		return true
+	}
+	for r, child := range ww.tree.children(node) {
+		next := ww.step(states, r)
+		if len(next) == 0 {
+			continue
+		}
+		if !ww.walk(child, appendLabel(buf, r, ww.tree.byteLabels()), next) {
+			return false
+		}
+	}
+	return true
+}
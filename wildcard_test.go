@@ -0,0 +1,72 @@
+package trietree_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/koron-go/trietree"
+)
+
+// wildcardMatch matches a key with a pattern rune by rune, like Match.
+func wildcardMatch(pat, key []rune) bool {
+	if len(pat) == 0 {
+		return len(key) == 0
+	}
+	switch pat[0] {
+	case '*':
+		for i := 0; i <= len(key); i++ {
+			if wildcardMatch(pat[1:], key[i:]) {
+				return true
+			}
+		}
+		return false
+	case '?':
+		return len(key) > 0 && wildcardMatch(pat[1:], key[1:])
+	default:
+		return len(key) > 0 && pat[0] == key[0] && wildcardMatch(pat[1:], key[1:])
+	}
+}
+
+type wildcardMatcher interface {
+	Match(pattern string) iter.Seq2[string, int]
+}
+
+func testMatch(t *testing.T, wm wildcardMatcher, keys []string) {
+	t.Helper()
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	for _, pat := range []string{"", "*", "?", "??", "ca?", "*tion", "c*t", "*a*", "a*b*c", "**", "a?c", "あ*", "*い", "x*", "*??", "?*?", "あ?"} {
+		want := []keyID{}
+		for _, k := range sorted {
+			if wildcardMatch([]rune(pat), []rune(k)) {
+				want = append(want, keyID{Key: k, ID: slices.Index(keys, k) + 1})
+			}
+		}
+		got := collectKeyIDs(wm.Match(pat))
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("unexpected matches for %q: -want +got\n%s", pat, d)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "aXbYc", "b", "cat", "cut", "cart", "car", "ct", "nation", "station", "tion", "あ", "あい"}
+	t.Run("dynamic", func(t *testing.T) {
+		testMatch(t, testDTreePut(t, &trietree.DTree{}, keys...), keys)
+	})
+	t.Run("static", func(t *testing.T) {
+		testMatch(t, trietree.Freeze(testDTreePut(t, &trietree.DTree{}, keys...)), keys)
+	})
+	t.Run("normalized", func(t *testing.T) {
+		dt := testDTreePut(t, &trietree.DTree{Normalizer: unicode.ToLower}, "Kelvin", "cat")
+		for _, wm := range []wildcardMatcher{dt, trietree.Freeze(dt)} {
+			want := []keyID{{"cat", 2}}
+			if d := cmp.Diff(want, collectKeyIDs(wm.Match("CA?"))); d != "" {
+				t.Errorf("unexpected matches for %T: -want +got\n%s", wm, d)
+			}
+		}
+	})
+}
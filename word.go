@@ -0,0 +1,43 @@
+package trietree
+
+import "unicode"
+
+// IsWordRune reports whether r is a part of words: a letter, a digit or an
+// underscore.  Letters of scripts which are written without spaces between
+// words, like Han, Hiragana, Katakana and Thai, are not treated as word runes.
+// So keys of those scripts match next to any runes, even if it is given as
+// WordRune of MatchOptions.
+func IsWordRune(r rune) bool {
+	if r == '_' {
+		return true
+	}
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+		return false
+	}
+	return !unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana,
+		unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
+
+// isBoundary reports whether s[start:end] is not a part of longer words.  It
+// is false when a rune next to either end of s[start:end] and the rune at
+// that end are both word runes.
+func isBoundary[S byteSeq](s S, start, end int, byteLabels bool, isWord func(rune) bool) bool {
+	if start >= end {
+		return true
+	}
+	if start > 0 {
+		if r, _ := decodeLastLabel(s[:start], byteLabels); isWord(r) {
+			if first, _ := decodeLabel(s[start:end], byteLabels); isWord(first) {
+				return false
+			}
+		}
+	}
+	if end < len(s) {
+		if r, _ := decodeLabel(s[end:], byteLabels); isWord(r) {
+			if last, _ := decodeLastLabel(s[start:end], byteLabels); isWord(last) {
+				return false
+			}
+		}
+	}
+	return true
+}
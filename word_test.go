@@ -0,0 +1,70 @@
+package trietree_test
+
+import (
+	"testing"
+
+	"github.com/koron-go/trietree"
+)
+
+func TestIsWordRune(t *testing.T) {
+	for _, c := range []struct {
+		r    rune
+		want bool
+	}{
+		{'a', true}, {'Z', true}, {'0', true}, {'_', true}, {'é', true},
+		{' ', false}, {'-', false}, {'.', false}, {'、', false},
+		{'猫', false}, {'ね', false}, {'コ', false}, {'ก', false},
+	} {
+		if got := trietree.IsWordRune(c.r); got != c.want {
+			t.Errorf("unexpected IsWordRune(%q): want=%t got=%t", c.r, c.want, got)
+		}
+	}
+}
+
+func TestWordRune(t *testing.T) {
+	const q = "cat concatenate cat. 猫cat ねこの"
+	want := []prediction{
+		{Start: 0, End: 3, ID: 1, Key: "cat"},
+		{Start: 16, End: 19, ID: 1, Key: "cat"},
+		{Start: 24, End: 27, ID: 1, Key: "cat"},
+		{Start: 28, End: 34, ID: 2, Key: "ねこ"},
+	}
+	for _, kind := range []trietree.MatchKind{trietree.Standard, trietree.LeftmostLongest, trietree.LeftmostFirst} {
+		dt := testDTreePut(t, &trietree.DTree{}, "cat", "ねこ")
+		opts := trietree.MatchOptions{Kind: kind, WordRune: trietree.IsWordRune}
+		for _, tree := range []optionsTree{dt, trietree.Freeze(dt), trietree.FreezeDA(dt)} {
+			testPredict(t, withOptions{tree, opts}, q, want)
+			testPredictIter(t, withOptions{tree, opts}, q, want)
+		}
+	}
+
+	dt := testDTreePut(t, &trietree.DTree{}, "ab", "b", "b c")
+	opts := trietree.MatchOptions{WordRune: trietree.IsWordRune}
+	exp := reports{
+		{0, 'a', nil},
+		{1, 'b', []node{{1, 2}}},
+		{2, ' ', nil},
+		{3, 'c', nil},
+	}
+	testScanWith(t, withOptions{dt, opts}, "ab c", exp)
+	testScanWith(t, withOptions{trietree.Freeze(dt), opts}, "ab c", exp)
+}
+
+func TestWordRune_mixedScripts(t *testing.T) {
+	// "Phone" is a part of a longer word "iPhone", but CJK keys next to
+	// Latin letters are not.
+	const q = "iPhoneの東京Tower"
+	want := []prediction{
+		{Start: 6, End: 9, ID: 1, Key: "の"},
+		{Start: 9, End: 15, ID: 2, Key: "東京"},
+		{Start: 15, End: 20, ID: 4, Key: "Tower"},
+	}
+	for _, kind := range []trietree.MatchKind{trietree.Standard, trietree.LeftmostLongest, trietree.LeftmostFirst} {
+		dt := testDTreePut(t, &trietree.DTree{}, "の", "東京", "Phone", "Tower")
+		opts := trietree.MatchOptions{Kind: kind, WordRune: trietree.IsWordRune}
+		for _, tree := range []optionsTree{dt, trietree.Freeze(dt), trietree.FreezeDA(dt)} {
+			testPredict(t, withOptions{tree, opts}, q, want)
+			testPredictIter(t, withOptions{tree, opts}, q, want)
+		}
+	}
+}